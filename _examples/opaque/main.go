@@ -7,12 +7,10 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 
 	"log"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -57,7 +55,7 @@ func getBookAuthor(w http.ResponseWriter, r *http.Request) {
 
 func newAuthenticator() *authenticator {
 	cache := libcache.FIFO.New(0)
-	db := &db{m: make(map[string]opaque.Token)}
+	db := &db{MemoryTokenStore: opaque.NewMemoryTokenStore()}
 	secret := opaque.StaticSecret([]byte("secret"))
 
 	refreshScope := token.NewScope("refresh", "/v1/auth/token", "GET")
@@ -143,36 +141,20 @@ func (a *authenticator) middleware(next http.Handler) http.HandlerFunc {
 }
 
 type db struct {
-	mu sync.Mutex
-	m  map[string]opaque.Token
+	*opaque.MemoryTokenStore
 }
 
-func (db db) Store(_ context.Context, t opaque.Token) error {
-	db.mu.Lock()
-	defer db.mu.Unlock()
-	db.m[t.Signature] = t
-	return nil
-}
-
-func (db db) Lookup(_ context.Context, sig string) (opaque.Token, error) {
-	db.mu.Lock()
-	defer db.mu.Unlock()
-
-	t, ok := db.m[sig]
-
-	if !ok {
-		return opaque.Token{}, errors.New("db: token not found")
+func (db db) Lookup(ctx context.Context, sig string) (opaque.Token, error) {
+	t, err := db.MemoryTokenStore.Lookup(ctx, sig)
+	if err != nil {
+		return opaque.Token{}, err
 	}
 
 	if t.Prefix == "r" {
 		// Refresh token is one time password so remove it
 		// To prevent user use it again.
-		delete(db.m, sig)
+		_ = db.Revoke(ctx, sig)
 	}
 
 	return t, nil
 }
-
-func (db db) Revoke(ctx context.Context, sig string) error {
-	return errors.New("revoke not implemented")
-}
@@ -0,0 +1,44 @@
+// Package authtest provides utilities for testing go-guardian based authentication.
+package authtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+var _ auth.Clock = (*FakeClock)(nil)
+
+// FakeClock implements auth.Clock and returns a manually controlled time,
+// it's safe for concurrent use.
+type FakeClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// Now returns the fake clock current time.
+func (f *FakeClock) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now
+}
+
+// Set sets the fake clock current time.
+func (f *FakeClock) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+}
+
+// Advance moves the fake clock current time forward by d.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// NewFakeClock return's a new fake clock set to the given time.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
@@ -0,0 +1,20 @@
+package authtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(now)
+	assert.Equal(t, now, c.Now())
+
+	c.Advance(time.Hour)
+	assert.Equal(t, now.Add(time.Hour), c.Now())
+
+	c.Set(now)
+	assert.Equal(t, now, c.Now())
+}
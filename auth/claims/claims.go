@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"strings"
 	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
 )
 
 const (
@@ -111,6 +113,17 @@ func (t *Time) UnmarshalJSON(b []byte) (err error) {
 	return nil
 }
 
+// Confirmation represents the confirmation (cnf) claim as defined in RFC 7800,
+// used to bind a token to a proof-of-possession key.
+type Confirmation struct {
+	// JWKThumbprint is the base64url-encoded SHA-256 JWK thumbprint
+	// of the DPoP proof key, as defined in RFC 9449.
+	JWKThumbprint string `json:"jkt,omitempty"`
+	// X509Thumbprint is the base64url-encoded SHA-256 thumbprint
+	// of the client certificate, as defined in RFC 8705.
+	X509Thumbprint string `json:"x5t#S256,omitempty"`
+}
+
 // VerifyOptions contains parameters for Standard.Verify.
 type VerifyOptions struct {
 	// Audience represents targeted claim audiences.
@@ -118,7 +131,7 @@ type VerifyOptions struct {
 	// Issuer represents claim issuer.
 	Issuer string
 	// Time returns the current time.
-	// If Time is nil, Standard.Verify uses time.Now with DefaultLeeway.
+	// If Time is nil, Standard.Verify uses Clock with Leeway.
 	// Recommended to add leeway window before return t to account for clock skew,
 	// https://tools.ietf.org/html/rfc7519#section-4.1.4.
 	//
@@ -126,10 +139,25 @@ type VerifyOptions struct {
 	//	        return time.Now().Add(-leeway)
 	//       }
 	Time func() (t time.Time)
+	// Clock provides the current time, used when Time is nil.
+	// Default auth.SystemClock.
+	Clock auth.Clock
+	// Leeway represents the allowed clock skew, used when Time is nil.
+	// A zero leeway disables the clock skew.
+	// Default DefaultLeeway.
+	Leeway *time.Duration
 	// Extra parameters.
 	Extra map[string]interface{}
 }
 
+// GetLeeway return's o.Leeway if set, Otherwise, DefaultLeeway.
+func (o VerifyOptions) GetLeeway() time.Duration {
+	if o.Leeway == nil {
+		return DefaultLeeway
+	}
+	return *o.Leeway
+}
+
 // Standard provide a starting point for a set of useful interoperable claims
 // as defined in RFC 7519.
 type Standard struct {
@@ -141,6 +169,8 @@ type Standard struct {
 	Subject   string       `json:"sub,omitempty"`
 	Issuer    string       `json:"iss,omitempty"`
 	JWTID     string       `json:"jti,omitempty"`
+
+	Confirmation *Confirmation `json:"cnf,omitempty"`
 }
 
 // Verify attempts to verify s using opts.
@@ -158,6 +188,8 @@ func (s Standard) Verify(opts VerifyOptions) error {
 				Subject:   s.Subject,
 				Issuer:    s.Issuer,
 				JWTID:     s.JWTID,
+
+				Confirmation: s.Confirmation,
 			},
 			Reason: r,
 		}
@@ -192,8 +224,14 @@ func (s Standard) Verify(opts VerifyOptions) error {
 	}
 
 	if opts.Time == nil {
+		clock, leeway := opts.Clock, opts.GetLeeway()
+
+		if clock == nil {
+			clock = auth.SystemClock
+		}
+
 		opts.Time = func() (t time.Time) {
-			return time.Now().Add(-DefaultLeeway)
+			return clock.Now().Add(-leeway)
 		}
 	}
 
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
 )
 
 func TestStringOrListUnmarshalJSON(t *testing.T) {
@@ -98,6 +100,8 @@ func TestVerify(t *testing.T) {
 		return &t
 	}
 
+	second, zero := time.Second, time.Duration(0)
+
 	table := []struct {
 		name  string
 		opt   VerifyOptions
@@ -177,6 +181,37 @@ func TestVerify(t *testing.T) {
 				Audience:  []string{"test"},
 			},
 		},
+		{
+			name: "it verify claim time against the provided clock",
+			opt: VerifyOptions{
+				Clock: authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			claim: Standard{
+				ExpiresAt: toTime(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			name: "it return error when claim expired within the default leeway but not the provided leeway",
+			opt: VerifyOptions{
+				Clock:  authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+				Leeway: &second,
+			},
+			claim: Standard{
+				ExpiresAt: toTime(time.Date(2019, 12, 31, 23, 59, 30, 0, time.UTC)),
+			},
+			err: InvalidError{Reason: Expired},
+		},
+		{
+			name: "it return error when claim expired and leeway is zero",
+			opt: VerifyOptions{
+				Clock:  authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+				Leeway: &zero,
+			},
+			claim: Standard{
+				ExpiresAt: toTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			err: InvalidError{Reason: Expired},
+		},
 	}
 
 	for _, tt := range table {
@@ -0,0 +1,22 @@
+package auth
+
+import "time"
+
+// Clock provides the current time to time-dependent components e.g token expiry, OTP intervals.
+// Typically used to inject a fake clock in tests, or to replay incidents at a given time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// ClockFunc is an adapter to allow the use of ordinary functions as Clock.
+type ClockFunc func() time.Time
+
+// Now calls fn().
+func (fn ClockFunc) Now() time.Time {
+	return fn()
+}
+
+// SystemClock implements Clock using time.Now,
+// and used by default across all components.
+var SystemClock Clock = ClockFunc(time.Now)
@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+)
+
+// ClientAssertionType represents the JWT bearer client assertion type as defined in RFC 7523.
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ErrClientAssertionAlg is returned by Requester Do method,
+// when the client assertion secrets keeper algorithm does not match the client authentication method.
+var ErrClientAssertionAlg = errors.New(
+	"strategies/oauth2: Client assertion signing algorithm does not match the authentication method",
+)
+
+// ClientAssertion signs a fresh client assertion JWT for each request,
+// to authenticate the client using private_key_jwt or client_secret_jwt,
+// as defined in RFC 7523 section 2.2 and OpenID Connect core section 9.
+type ClientAssertion struct {
+	ClientID string
+	// Audience default to the request URL.
+	Audience string
+	Keeper   jwt.SecretsKeeper
+	// HMAC reports whether the keeper must hold an HMAC secret (client_secret_jwt),
+	// or an asymmetric private key (private_key_jwt).
+	HMAC bool
+	// Lifetime of the assertion, default 1 minute.
+	Lifetime time.Duration
+	Clock    auth.Clock
+}
+
+func (c *ClientAssertion) sign(aud string) (string, error) {
+	_, alg, err := c.Keeper.Get(c.Keeper.KID())
+	if err != nil {
+		return "", err
+	}
+
+	if strings.HasPrefix(alg, "HS") != c.HMAC {
+		return "", ErrClientAssertionAlg
+	}
+
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+
+	if len(c.Audience) > 0 {
+		aud = c.Audience
+	}
+
+	now := c.Clock.Now().UTC()
+	exp := now.Add(c.Lifetime)
+
+	return jwt.IssueToken(c.Keeper, claims.Standard{
+		Issuer:    c.ClientID,
+		Subject:   c.ClientID,
+		Audience:  claims.StringOrList{aud},
+		IssuedAt:  (*claims.Time)(&now),
+		ExpiresAt: (*claims.Time)(&exp),
+		JWTID:     base64.RawURLEncoding.EncodeToString(b),
+	})
+}
+
+// apply adds the client assertion parameters to the request form body,
+// a GET or HEAD request sent as POST to not leak the assertion into the request URL.
+func (c *ClientAssertion) apply(r *http.Request) error {
+	aud := *r.URL
+	aud.RawQuery = ""
+
+	assertion, err := c.sign(aud.String())
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("client_id", c.ClientID)
+	params.Set("client_assertion_type", ClientAssertionType)
+	params.Set("client_assertion", assertion)
+
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		r.Method = http.MethodPost
+	}
+
+	body := []byte{}
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		r.Body.Close()
+	}
+
+	if len(body) > 0 {
+		body = append(body, '&')
+	}
+
+	body = append(body, params.Encode()...)
+
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ContentLength = int64(len(body))
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return nil
+}
+
+func (r *Requester) clientAssertion() *ClientAssertion {
+	if r.ClientAssertion == nil {
+		r.ClientAssertion = &ClientAssertion{
+			Lifetime: time.Minute,
+			Clock:    auth.SystemClock,
+		}
+	}
+	return r.ClientAssertion
+}
+
+// SetRequesterClientAssertion sets requester to authenticate the client,
+// using a client assertion JWT signed by the given keeper for each request.
+// hmac reports whether the keeper holds the client secret (client_secret_jwt),
+// or the client private key (private_key_jwt).
+func SetRequesterClientAssertion(clientID string, k jwt.SecretsKeeper, hmac bool) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Requester); ok {
+			c := r.clientAssertion()
+			c.ClientID = clientID
+			c.Keeper = k
+			c.HMAC = hmac
+		}
+	})
+}
+
+// SetRequesterClientAssertionAudience sets requester client assertion aud claim.
+func SetRequesterClientAssertionAudience(aud string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Requester); ok {
+			r.clientAssertion().Audience = aud
+		}
+	})
+}
+
+// SetRequesterClientAssertionLifetime sets requester client assertion lifetime.
+func SetRequesterClientAssertionLifetime(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Requester); ok {
+			r.clientAssertion().Lifetime = d
+		}
+	})
+}
+
+// SetRequesterClientAssertionClock sets requester client assertion clock,
+// used to compute the assertion iat and exp claims.
+func SetRequesterClientAssertionClock(c auth.Clock) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Requester); ok {
+			r.clientAssertion().Clock = c
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package jwt
+
+import (
+	"crypto"
+	"errors"
+
+	"gopkg.in/go-jose/go-jose.v2"
+	"gopkg.in/go-jose/go-jose.v2/jwt"
+)
+
+const headerEnc = "enc"
+
+// ErrUnsupportedAlg is returned by Authenticate Strategy method,
+// when jwt token key management or content encryption algorithm not allowed.
+var ErrUnsupportedAlg = errors.New("Unsupported encryption algorithm, token alg or enc header not allowed")
+
+// keyAlgorithms hold the allowed key management algorithms,
+// the value reports whether the algorithm is symmetric.
+//
+// RSA1_5 and PBES2 intentionally not allowed.
+var keyAlgorithms = map[string]bool{
+	string(jose.RSA_OAEP):       false,
+	string(jose.RSA_OAEP_256):   false,
+	string(jose.ECDH_ES):        false,
+	string(jose.ECDH_ES_A128KW): false,
+	string(jose.ECDH_ES_A192KW): false,
+	string(jose.ECDH_ES_A256KW): false,
+	string(jose.DIRECT):         true,
+	string(jose.A128KW):         true,
+	string(jose.A192KW):         true,
+	string(jose.A256KW):         true,
+	string(jose.A128GCMKW):      true,
+	string(jose.A192GCMKW):      true,
+	string(jose.A256GCMKW):      true,
+}
+
+var contentEncryptions = map[string]struct{}{
+	string(jose.A128GCM):       {},
+	string(jose.A192GCM):       {},
+	string(jose.A256GCM):       {},
+	string(jose.A128CBC_HS256): {},
+	string(jose.A192CBC_HS384): {},
+	string(jose.A256CBC_HS512): {},
+}
+
+// IssueEncryptedToken issue encrypted jwt access token from the given dest, with the given typ header,
+// using the encryption keeper key management algorithm and the given content encryption.
+//
+// The token signed then encrypted (nested) when sign keeper provided,
+// Otherwise, the token only encrypted and the encryption keeper algorithm must be symmetric.
+func IssueEncryptedToken(s, e SecretsKeeper, typ, enc string, dest ...interface{}) (string, error) {
+	kid := e.KID()
+	key, alg, err := e.Get(kid)
+	if err != nil {
+		return "", err
+	}
+
+	if err := allowed(alg, enc, s == nil); err != nil {
+		return "", err
+	}
+
+	if v, ok := key.(crypto.Signer); ok {
+		key = v.Public()
+	}
+
+	rcpt := jose.Recipient{Algorithm: jose.KeyAlgorithm(alg), Key: key, KeyID: kid}
+	opts := new(jose.EncrypterOptions).WithType(jose.ContentType(typ))
+
+	if s != nil {
+		opts = new(jose.EncrypterOptions).WithType(TokenType).WithContentType(TokenType)
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.ContentEncryption(enc), rcpt, opts)
+	if err != nil {
+		return "", err
+	}
+
+	if s == nil {
+		builder := jwt.Encrypted(encrypter)
+		for _, v := range dest {
+			builder = builder.Claims(v)
+		}
+		return builder.CompactSerialize()
+	}
+
+	sig, err := signer(s, typ)
+	if err != nil {
+		return "", err
+	}
+
+	builder := jwt.SignedAndEncrypted(sig, encrypter)
+	for _, v := range dest {
+		builder = builder.Claims(v)
+	}
+
+	return builder.CompactSerialize()
+}
+
+// ParseEncryptedToken parse encrypted jwt access token to the given dest.
+// The token enc header must be one of the given content encryptions,
+// and the token typ header must match the given typ if not empty.
+// The nested token signing algorithm and key verified using the given policy if not nil.
+//
+// The token must be signed then encrypted (nested) when sign keeper provided,
+// Otherwise, the token must be only encrypted using a symmetric key management algorithm.
+func ParseEncryptedToken(
+	s, e SecretsKeeper,
+	p *Policy,
+	typ string,
+	encs []string,
+	token string,
+	dest ...interface{},
+) error {
+	var (
+		header jose.Header
+		nested *jwt.NestedJSONWebToken
+		jt     *jwt.JSONWebToken
+		err    error
+	)
+
+	if s != nil {
+		nested, err = jwt.ParseSignedAndEncrypted(token)
+		if err == nil {
+			header = nested.Headers[0]
+		}
+	} else {
+		jt, err = jwt.ParseEncrypted(token)
+		if err == nil {
+			header = jt.Headers[0]
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(header.KeyID) == 0 {
+		return ErrMissingKID
+	}
+
+	key, alg, err := e.Get(header.KeyID)
+	if err != nil {
+		return err
+	}
+
+	if header.Algorithm != alg {
+		return ErrInvalidAlg
+	}
+
+	enc, _ := header.ExtraHeaders[headerEnc].(string)
+	if err := allowed(alg, enc, s == nil); err != nil {
+		return err
+	}
+
+	if !contains(encs, enc) {
+		return ErrUnsupportedAlg
+	}
+
+	if s == nil {
+		if !matchType(header, typ) {
+			return ErrInvalidType
+		}
+		return jt.Claims(key, dest...)
+	}
+
+	jt, err = nested.Decrypt(key)
+	if err != nil {
+		return err
+	}
+
+	return parseClaims(s, p, jt, typ, dest...)
+}
+
+// allowed reports whether the key management and content encryption algorithms allowed.
+func allowed(alg, enc string, symmetric bool) error {
+	sym, ok := keyAlgorithms[alg]
+	if !ok || (symmetric && !sym) {
+		return ErrUnsupportedAlg
+	}
+
+	if _, ok := contentEncryptions[enc]; !ok {
+		return ErrUnsupportedAlg
+	}
+
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
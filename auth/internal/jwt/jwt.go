@@ -3,12 +3,22 @@ package jwt
 import (
 	"crypto"
 	"errors"
+	"strings"
 
 	"gopkg.in/go-jose/go-jose.v2"
 	"gopkg.in/go-jose/go-jose.v2/jwt"
 )
 
-const headerKID = "kid"
+const (
+	headerKID = "kid"
+	headerTyp = "typ"
+
+	// TokenType represents the default JWT type header.
+	TokenType = "JWT"
+
+	// AccessTokenType represents the JWT access token type header as defined in RFC 9068.
+	AccessTokenType = "at+jwt"
+)
 
 var (
 	// ErrMissingKID is returned by Authenticate Strategy method,
@@ -18,6 +28,10 @@ var (
 	// ErrInvalidAlg is returned by Authenticate Strategy method,
 	// when jwt token alg header does not match key algorithm.
 	ErrInvalidAlg = errors.New("Invalid signing algorithm, token alg header does not match key algorithm")
+
+	// ErrInvalidType is returned by Authenticate Strategy method,
+	// when jwt token typ header does not match the expected type.
+	ErrInvalidType = errors.New("Invalid token type, token typ header does not match the expected type")
 )
 
 // SecretsKeeper hold all secrets/keys to sign and parse JWT token
@@ -32,16 +46,12 @@ type SecretsKeeper interface {
 
 // IssueToken issue jwt access token from the given dest.
 func IssueToken(k SecretsKeeper, dest ...interface{}) (string, error) {
-	kid := k.KID()
-	secret, alg, err := k.Get(kid)
-	if err != nil {
-		return "", err
-	}
-
-	opt := (&jose.SignerOptions{}).WithType("JWT").WithHeader(headerKID, kid)
-	key := jose.SigningKey{Algorithm: jose.SignatureAlgorithm(alg), Key: secret}
-	sig, err := jose.NewSigner(key, opt)
+	return IssueTypedToken(k, TokenType, dest...)
+}
 
+// IssueTypedToken issue jwt access token from the given dest, with the given typ header.
+func IssueTypedToken(k SecretsKeeper, typ string, dest ...interface{}) (string, error) {
+	sig, err := signer(k, typ)
 	if err != nil {
 		return "", err
 	}
@@ -56,15 +66,63 @@ func IssueToken(k SecretsKeeper, dest ...interface{}) (string, error) {
 
 // ParseToken parse jwt access token to the given dest.
 func ParseToken(k SecretsKeeper, token string, dest ...interface{}) error {
+	return ParseTypedToken(k, "", token, dest...)
+}
+
+// ParseTypedToken parse jwt access token to the given dest,
+// The token typ header must match the given typ if not empty.
+func ParseTypedToken(k SecretsKeeper, typ, token string, dest ...interface{}) error {
+	return ParseTokenWithPolicy(k, nil, typ, token, dest...)
+}
+
+// ParseTokenWithPolicy parse jwt access token to the given dest,
+// and verifies the token signing algorithm and key using the given policy if not nil.
+// The token typ header must match the given typ if not empty.
+//
+// When policy provided, and the secret/key has no algorithm,
+// the token alg header used as long as the policy allows it.
+func ParseTokenWithPolicy(k SecretsKeeper, p *Policy, typ, token string, dest ...interface{}) error {
+	_, err := ParseTokenWithHeader(k, p, typ, token, dest...)
+	return err
+}
+
+// ParseTokenWithHeader is similar to ParseTokenWithPolicy,
+// but return's the verified token header.
+func ParseTokenWithHeader(k SecretsKeeper, p *Policy, typ, token string, dest ...interface{}) (jose.Header, error) {
 	jt, err := jwt.ParseSigned(token)
 	if err != nil {
-		return err
+		return jose.Header{}, err
 	}
 
+	if err := parseClaims(k, p, jt, typ, dest...); err != nil {
+		return jose.Header{}, err
+	}
+
+	return jt.Headers[0], nil
+}
+
+func signer(k SecretsKeeper, typ string) (jose.Signer, error) {
+	kid := k.KID()
+	secret, alg, err := k.Get(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := (&jose.SignerOptions{}).WithType(jose.ContentType(typ)).WithHeader(headerKID, kid)
+	key := jose.SigningKey{Algorithm: jose.SignatureAlgorithm(alg), Key: secret}
+	return jose.NewSigner(key, opt)
+}
+
+// parseClaims verify the signed jwt token and deserializes its claims into dest.
+func parseClaims(k SecretsKeeper, p *Policy, jt *jwt.JSONWebToken, typ string, dest ...interface{}) error {
 	if len(jt.Headers) == 0 {
 		return errors.New("No headers found in JWT token")
 	}
 
+	if !matchType(jt.Headers[0], typ) {
+		return ErrInvalidType
+	}
+
 	if len(jt.Headers[0].KeyID) == 0 {
 		return ErrMissingKID
 	}
@@ -75,13 +133,38 @@ func ParseToken(k SecretsKeeper, token string, dest ...interface{}) error {
 		return err
 	}
 
+	if p != nil && len(alg) == 0 {
+		alg = jt.Headers[0].Algorithm
+	}
+
 	if jt.Headers[0].Algorithm != alg {
 		return ErrInvalidAlg
 	}
 
+	if p != nil {
+		if err := p.Verify(alg, secret); err != nil {
+			return err
+		}
+	}
+
 	if v, ok := secret.(crypto.Signer); ok {
 		secret = v.Public()
 	}
 
 	return jt.Claims(secret, dest...)
 }
+
+// matchType reports whether the header typ matches the given typ,
+// ignoring case and the optional "application/" prefix as defined in RFC 7515.
+func matchType(h jose.Header, typ string) bool {
+	if len(typ) == 0 {
+		return true
+	}
+
+	normalize := func(s string) string {
+		return strings.TrimPrefix(strings.ToLower(s), "application/")
+	}
+
+	v, _ := h.ExtraHeaders[headerTyp].(string)
+	return normalize(v) == normalize(typ)
+}
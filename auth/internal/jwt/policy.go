@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"strings"
+)
+
+// PolicyReason represents signing policy rejection reason.
+type PolicyReason int
+
+const (
+	// AlgorithmNotAllowed results when the token signing algorithm,
+	// is unsupported or not one of the policy allowed algorithms.
+	AlgorithmNotAllowed PolicyReason = iota
+	// HMACNotAllowed results when the token signed using HMAC algorithm,
+	// and the policy allows only asymmetric algorithms.
+	HMACNotAllowed
+	// KeyTypeMismatch results when the key type does not match the signing algorithm family,
+	// e.g. RSA key used with ES256.
+	KeyTypeMismatch
+	// WeakKey results when the key size is less than the policy minimum size,
+	// or HMAC secret shorter than the hash output as required by RFC 7518.
+	WeakKey
+)
+
+// PolicyError results when the token signing algorithm or key rejected by the signing policy.
+type PolicyError struct {
+	Algorithm string
+	Reason    PolicyReason
+}
+
+func (e PolicyError) Error() string {
+	switch e.Reason {
+	case AlgorithmNotAllowed:
+		return "jwt: signing algorithm " + e.Algorithm + " not allowed"
+	case HMACNotAllowed:
+		return "jwt: HMAC signing algorithm " + e.Algorithm + " not allowed, only asymmetric algorithms allowed"
+	case KeyTypeMismatch:
+		return "jwt: key type does not match signing algorithm " + e.Algorithm
+	case WeakKey:
+		return "jwt: key size too small for signing algorithm " + e.Algorithm
+	}
+
+	return "jwt: unknown signing policy error"
+}
+
+// Policy represents jwt token signature verification policy.
+type Policy struct {
+	// Algorithms represents the allowed signing algorithms,
+	// If empty, all supported algorithms allowed.
+	Algorithms []string
+	// AsymmetricOnly refuse HMAC signing algorithms.
+	AsymmetricOnly bool
+	// MinRSAKeySize represents the minimum RSA key size in bits.
+	MinRSAKeySize int
+	// MinECKeySize represents the minimum EC key size (curve bits).
+	MinECKeySize int
+}
+
+var families = map[string]struct{}{
+	"HS":    {},
+	"RS":    {},
+	"PS":    {},
+	"ES":    {},
+	"EdDSA": {},
+}
+
+// Verify reports an error if the signing algorithm or key rejected by the policy.
+func (p Policy) Verify(alg string, key interface{}) error {
+	fail := func(r PolicyReason) error {
+		return PolicyError{Algorithm: alg, Reason: r}
+	}
+
+	family := strings.TrimRight(alg, "0123456789")
+	if _, ok := families[family]; !ok {
+		return fail(AlgorithmNotAllowed)
+	}
+
+	if len(p.Algorithms) > 0 && !contains(p.Algorithms, alg) {
+		return fail(AlgorithmNotAllowed)
+	}
+
+	if p.AsymmetricOnly && family == "HS" {
+		return fail(HMACNotAllowed)
+	}
+
+	if v, ok := key.(crypto.Signer); ok {
+		key = v.Public()
+	}
+
+	switch k := key.(type) {
+	case []byte:
+		if family != "HS" {
+			return fail(KeyTypeMismatch)
+		}
+		if len(k)*8 < hashSize(alg) {
+			return fail(WeakKey)
+		}
+	case *rsa.PublicKey:
+		if family != "RS" && family != "PS" {
+			return fail(KeyTypeMismatch)
+		}
+		if k.N.BitLen() < p.MinRSAKeySize {
+			return fail(WeakKey)
+		}
+	case *ecdsa.PublicKey:
+		if family != "ES" || hashSize(alg) != curveHashSize(k.Curve.Params().BitSize) {
+			return fail(KeyTypeMismatch)
+		}
+		if k.Curve.Params().BitSize < p.MinECKeySize {
+			return fail(WeakKey)
+		}
+	case ed25519.PublicKey:
+		if family != "EdDSA" {
+			return fail(KeyTypeMismatch)
+		}
+	default:
+		return fail(KeyTypeMismatch)
+	}
+
+	return nil
+}
+
+// hashSize return's the signing algorithm hash output size in bits.
+func hashSize(alg string) int {
+	switch {
+	case strings.HasSuffix(alg, "256"):
+		return 256
+	case strings.HasSuffix(alg, "384"):
+		return 384
+	case strings.HasSuffix(alg, "512"):
+		return 512
+	}
+	return 0
+}
+
+// curveHashSize return's the hash size used with the given curve as defined in RFC 7518,
+// P-256 with SHA-256, P-384 with SHA-384, and P-521 with SHA-512.
+func curveHashSize(bits int) int {
+	if bits == 521 {
+		return 512
+	}
+	return bits
+}
@@ -0,0 +1,62 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+)
+
+var (
+	// ErrMissingClaim is returned by Authenticate Strategy method,
+	// when jwt access token missing a required claim.
+	ErrMissingClaim = errors.New("Token missing required claim")
+
+	// ErrMissingAudience is returned by Authenticate Strategy method,
+	// when the expected audience not configured to validate jwt access token.
+	ErrMissingAudience = errors.New("Access token profile requires an expected audience")
+)
+
+// AccessTokenClaims represents the JWT access token claims as defined in RFC 9068.
+type AccessTokenClaims struct {
+	ClientID string `json:"client_id,omitempty"`
+	claims.Standard
+}
+
+// Verify attempts to verify c using opts,
+// and reports an error if a required claim missing or opts does not restrict the audience.
+func (c AccessTokenClaims) Verify(opts claims.VerifyOptions) error {
+	if !nonEmpty(opts.Audience...) {
+		return ErrMissingAudience
+	}
+
+	required := []struct {
+		name string
+		ok   bool
+	}{
+		{"iss", len(c.Issuer) > 0},
+		{"exp", c.ExpiresAt != nil},
+		{"aud", nonEmpty(c.Audience...)},
+		{"sub", len(c.Subject) > 0},
+		{"client_id", len(c.ClientID) > 0},
+		{"iat", c.IssuedAt != nil},
+		{"jti", len(c.JWTID) > 0},
+	}
+
+	for _, r := range required {
+		if !r.ok {
+			return fmt.Errorf("%w %s", ErrMissingClaim, r.name)
+		}
+	}
+
+	return c.Standard.Verify(opts)
+}
+
+func nonEmpty(s ...string) bool {
+	for _, v := range s {
+		if len(v) > 0 {
+			return true
+		}
+	}
+	return false
+}
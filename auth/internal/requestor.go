@@ -28,6 +28,10 @@ type Requester struct {
 	AdditionalData func(r *http.Request)
 	Unmarshal      func(data []byte, v interface{}) error
 	Marshal        func(v interface{}) ([]byte, error)
+	// ClientAssertion authenticate the client using a signed JWT, if not nil.
+	ClientAssertion *ClientAssertion
+	// TokenSource supplies the Authorization bearer token, if not nil.
+	TokenSource TokenSource
 }
 
 // Do sends the HTTP request and parse the HTTP response.
@@ -42,16 +46,50 @@ func (r *Requester) DoWithf(ctx context.Context, f func(*http.Request), data, re
 }
 
 func (r *Requester) do(ctx context.Context, f func(r *http.Request), data, review, status interface{}) (*http.Response, error) { //nolint:lll
+	token := ""
+
+	resp, body, err := r.send(ctx, f, data, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	// retry once with a fresh token, the server may have revoked the cached one.
+	if i, ok := r.TokenSource.(TokenInvalidator); ok && resp.StatusCode == http.StatusUnauthorized {
+		i.Invalidate(token)
+		resp, body, err = r.send(ctx, f, data, &token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if body == nil {
+		return resp, nil
+	}
+
+	if err := r.Unmarshal(body, status); err == nil && !r.KeepUnmarshalling {
+		return resp, nil
+	}
+
+	if err := r.Unmarshal(body, review); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal response body data, Type: %T Err: %w", review, err)
+	}
+
+	return resp, nil
+}
+
+// send sends the HTTP request and read the HTTP response body,
+// the body is nil when the response has no body.
+func (r *Requester) send(ctx context.Context, f func(r *http.Request), data interface{}, token *string) (*http.Response, []byte, error) { //nolint:lll
 	url := r.Addr + r.Endpoint
 
 	reader, err := r.reader(data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, r.Method, url, reader)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create new HTTP request, Method: %s, URL: %s, Err: %w", r.Method, url, err)
+		return nil, nil, fmt.Errorf("Failed to create new HTTP request, Method: %s, URL: %s, Err: %w", r.Method, url, err)
 	}
 
 	f(req)
@@ -60,31 +98,37 @@ func (r *Requester) do(ctx context.Context, f func(r *http.Request), data, revie
 		r.AdditionalData(req)
 	}
 
-	resp, err := r.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to send the HTTP request, Method: POST, URL: %s, Err: %w", url, err)
+	if r.TokenSource != nil {
+		*token, err = r.TokenSource.Token(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to retrieve the authorization token, Err: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+*token)
 	}
 
-	if resp.Body == http.NoBody {
-		return resp, nil
+	if r.ClientAssertion != nil && r.ClientAssertion.Keeper != nil {
+		if err := r.ClientAssertion.apply(req); err != nil {
+			return nil, nil, fmt.Errorf("Failed to sign the client assertion, Err: %w", err)
+		}
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	resp, err := r.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to read the HTTP response, Method: POST, URL: %s, Err: %w", url, err)
+		return nil, nil, fmt.Errorf("Failed to send the HTTP request, Method: POST, URL: %s, Err: %w", url, err)
 	}
 
-	defer resp.Body.Close()
-
-	if err := r.Unmarshal(body, status); err == nil && !r.KeepUnmarshalling {
-		return resp, nil
+	if resp.Body == http.NoBody {
+		return resp, nil, nil
 	}
 
-	if err := r.Unmarshal(body, review); err != nil {
-		return nil, fmt.Errorf("Failed to unmarshal response body data, Type: %T Err: %w", review, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to read the HTTP response, Method: POST, URL: %s, Err: %w", url, err)
 	}
 
-	return resp, nil
+	return resp, body, nil
 }
 
 func (r *Requester) reader(data interface{}) (io.Reader, error) {
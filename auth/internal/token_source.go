@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+// TokenSource supplies the bearer token to authorize outgoing request's.
+type TokenSource interface {
+	// Token return's a valid access token.
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenInvalidator is an optional interface that may be implemented by a TokenSource,
+// to discard a token rejected by the server (401), so the next Token call return's a fresh one.
+// The rejected request retried once, only if the TokenSource implements TokenInvalidator.
+type TokenInvalidator interface {
+	// Invalidate discards the given token if it still the current one.
+	Invalidate(token string)
+}
+
+// SetRequesterTokenSource sets requester token source,
+// to authorize each request with a bearer token retrieved from ts.
+func SetRequesterTokenSource(ts TokenSource) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Requester); ok {
+			r.TokenSource = ts
+		}
+	})
+}
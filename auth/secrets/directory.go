@@ -0,0 +1,526 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/go-jose/go-jose.v2"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+)
+
+// ErrNoSigningKey is returned by Directory keeper,
+// when no private key found to sign new tokens.
+var ErrNoSigningKey = errors.New("secrets: No signing key found")
+
+// Directory implements jwt.SecretsKeeper, and opaque.SecretsKeeper using its HMAC secrets,
+// and loads keys from a directory e.g kubernetes secret mounted as files.
+//
+// Directory loads PEM encoded private/public keys and certificates from files with .pem, .crt, or .key extension,
+// e.g kubernetes TLS secret tls.crt and tls.key, and JWK or JWK Set from files with .json or .jwk extension.
+// Only the leaf certificate of a certificates chain loaded.
+// The key id derived from the file name without extension or JWK kid,
+// Or from the key SHA-256 thumbprint when SetThumbprintKID option provided.
+// A private key and its certificate or public key sharing the same key id loaded as one key.
+// The key algorithm inferred from the key type, unless declared by JWK alg or SetKeyAlgorithm option.
+//
+// The signing key id read from the signing key marker file (default "current"),
+// Otherwise, the most recently modified private key used to sign new tokens,
+// modification time ties broken by the greatest file name.
+//
+// Directory periodically checks the files for changes and reloads the keys.
+type Directory struct {
+	mu          sync.RWMutex
+	dir         string
+	marker      string
+	interval    time.Duration
+	thumbprint  bool
+	algs        map[string]string
+	clock       auth.Clock
+	checked     time.Time
+	fingerprint string
+	current     string
+	keys        []*entry // signing key first, then newest first.
+}
+
+// NewDirectory return's a new secrets keeper loading keys from the given directory.
+func NewDirectory(dir string, opts ...auth.Option) (*Directory, error) {
+	d := &Directory{
+		dir:      dir,
+		marker:   "current",
+		interval: time.Minute,
+		algs:     make(map[string]string),
+		clock:    auth.SystemClock,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(d)
+	}
+
+	if err := d.Reload(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// KID return's the signing key id.
+func (d *Directory) KID() string {
+	d.check()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.current
+}
+
+// KIDs return's all keys ids.
+func (d *Directory) KIDs() ([]string, error) {
+	d.check()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	ids := make([]string, 0, len(d.keys))
+	for _, e := range d.keys {
+		ids = append(ids, e.ID)
+	}
+
+	return ids, nil
+}
+
+// Get return's secret/key and the corresponding sign algorithm.
+func (d *Directory) Get(kid string) (interface{}, string, error) {
+	d.check()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, e := range d.keys {
+		if e.ID == kid {
+			return e.key, e.Algorithm, nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("%w %s", ErrInvalidKID, kid)
+}
+
+// Keys return's all HMAC secrets to sign and parse opaque token, the signing key first,
+// private keys skipped to not use their material as HMAC secrets.
+func (d *Directory) Keys() ([][]byte, error) {
+	d.check()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	keys := make([][]byte, 0, len(d.keys))
+	for _, e := range d.keys {
+		if len(e.Secret) > 0 && isHMAC(e.Algorithm) {
+			keys = append(keys, e.Secret)
+		}
+	}
+
+	return keys, nil
+}
+
+// Reload loads the keys from the directory,
+// the previously loaded keys kept on error.
+func (d *Directory) Reload() error {
+	fp, err := d.stat()
+	if err != nil {
+		return err
+	}
+
+	keys, current, err := d.load()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.keys = keys
+	d.current = current
+	d.fingerprint = fp
+	d.checked = d.clock.Now()
+
+	return nil
+}
+
+// check reloads the keys if the check interval elapsed and the files changed.
+func (d *Directory) check() {
+	d.mu.RLock()
+	due := d.clock.Now().Sub(d.checked) >= d.interval
+	fingerprint := d.fingerprint
+	d.mu.RUnlock()
+
+	if !due {
+		return
+	}
+
+	d.mu.Lock()
+	d.checked = d.clock.Now()
+	d.mu.Unlock()
+
+	if fp, err := d.stat(); err == nil && fp != fingerprint {
+		_ = d.Reload()
+	}
+}
+
+// stat return's a fingerprint of the directory files names, sizes, and modification times.
+func (d *Directory) stat() (string, error) {
+	files, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return "", err
+	}
+
+	sb := new(strings.Builder)
+	for _, f := range files {
+		// follow symlinks, kubernetes mounts secrets as symlinks.
+		fi, err := os.Stat(filepath.Join(d.dir, f.Name()))
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		sb.WriteString(f.Name() + ":" + strconv.FormatInt(fi.Size(), 10) + ":")
+		sb.WriteString(strconv.FormatInt(fi.ModTime().UnixNano(), 10) + ";")
+	}
+
+	return sb.String(), nil
+}
+
+func (d *Directory) load() ([]*entry, string, error) {
+	files, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keys := []*entry{}
+	seen := make(map[string]int)
+
+	for _, f := range files {
+		path := filepath.Join(d.dir, f.Name())
+		ext := strings.ToLower(filepath.Ext(f.Name()))
+		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+
+		pemFile := ext == ".pem" || ext == ".crt" || ext == ".key"
+
+		if !pemFile && ext != ".json" && ext != ".jwk" {
+			continue
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil || fi.IsDir() {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var entries []*entry
+		if pemFile {
+			entries, err = d.parsePEM(name, data)
+		} else {
+			entries, err = d.parseJWK(name, data)
+		}
+
+		if err != nil {
+			return nil, "", fmt.Errorf("secrets: Failed to load %s, %w", f.Name(), err)
+		}
+
+		for _, e := range entries {
+			e.CreatedAt = fi.ModTime()
+			e.file = f.Name()
+
+			i, ok := seen[e.ID]
+			if !ok {
+				seen[e.ID] = len(keys)
+				keys = append(keys, e)
+				continue
+			}
+
+			p, ok := pair(keys[i], e)
+			if !ok {
+				return nil, "", fmt.Errorf("secrets: Duplicate kid %s in %s and %s", e.ID, keys[i].file, f.Name())
+			}
+
+			keys[i] = p
+		}
+	}
+
+	current, err := d.signingKey(keys)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].ID == current || keys[j].ID == current {
+			return keys[i].ID == current
+		}
+		return newer(keys[i], keys[j])
+	})
+
+	return keys, current, nil
+}
+
+func (d *Directory) signingKey(keys []*entry) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(d.dir, d.marker))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if kid := strings.TrimSpace(string(data)); len(kid) > 0 {
+		for _, e := range keys {
+			if e.ID == kid && canSign(e.key) {
+				return kid, nil
+			}
+		}
+		return "", fmt.Errorf("%w, marker refer to %s", ErrNoSigningKey, kid)
+	}
+
+	var latest *entry
+	for _, e := range keys {
+		if canSign(e.key) && (latest == nil || newer(e, latest)) {
+			latest = e
+		}
+	}
+
+	if latest == nil {
+		// public keys only, the keeper used only to parse tokens.
+		return "", nil
+	}
+
+	return latest.ID, nil
+}
+
+func (d *Directory) parsePEM(name string, data []byte) ([]*entry, error) {
+	entries := []*entry{}
+	leaf := false
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type == "CERTIFICATE" {
+			// skip the intermediates certificates of a chain.
+			if leaf {
+				continue
+			}
+			leaf = true
+		}
+
+		key, err := parsePEMBlock(block)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := d.newEntry(name, "", key)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if len(entries) > 1 && !d.thumbprint {
+		return nil, errors.New("multiple PEM blocks require thumbprint kid")
+	}
+
+	return entries, nil
+}
+
+func (d *Directory) parseJWK(name string, data []byte) ([]*entry, error) {
+	jwks := []jose.JSONWebKey{}
+	set := new(jose.JSONWebKeySet)
+
+	if err := json.Unmarshal(data, set); err == nil && len(set.Keys) > 0 {
+		jwks = set.Keys
+	} else {
+		jwk := jose.JSONWebKey{}
+		if err := json.Unmarshal(data, &jwk); err != nil {
+			return nil, err
+		}
+		jwks = append(jwks, jwk)
+	}
+
+	entries := make([]*entry, 0, len(jwks))
+
+	for _, jwk := range jwks {
+		kid := jwk.KeyID
+		if len(kid) == 0 {
+			if len(jwks) > 1 && !d.thumbprint {
+				return nil, errors.New("JWK Set keys require kid")
+			}
+			kid = name
+		}
+
+		e, err := d.newEntry(kid, jwk.Algorithm, jwk.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (d *Directory) newEntry(kid, alg string, key interface{}) (*entry, error) {
+	if d.thumbprint {
+		jwk := jose.JSONWebKey{Key: key}
+		tp, err := jwk.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		kid = base64.RawURLEncoding.EncodeToString(tp)
+	}
+
+	if v, ok := d.algs[kid]; ok {
+		alg = v
+	}
+
+	if len(alg) == 0 {
+		alg = inferAlgorithm(key)
+	}
+
+	if len(alg) == 0 {
+		return nil, fmt.Errorf("unable to infer %s key algorithm", kid)
+	}
+
+	e := &entry{
+		Key: Key{
+			ID:        kid,
+			Algorithm: alg,
+		},
+		key: key,
+	}
+
+	if canSign(key) {
+		secret, err := marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		e.Secret = secret
+	}
+
+	return e, nil
+}
+
+func parsePEMBlock(block *pem.Block) (interface{}, error) {
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	}
+
+	return nil, errors.New("unsupported PEM block type " + block.Type)
+}
+
+// newer reports whether entry a is newer than b,
+// modification time ties broken by the greatest file name.
+func newer(a, b *entry) bool {
+	if a.CreatedAt.Equal(b.CreatedAt) {
+		return a.file > b.file
+	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
+
+// pair return's the private key entry of the given entries,
+// if the other entry is its certificate or public key e.g tls.key and tls.crt.
+func pair(a, b *entry) (*entry, bool) {
+	if canSign(b.key) {
+		a, b = b, a
+	}
+
+	signer, ok := a.key.(crypto.Signer)
+	if !ok || canSign(b.key) {
+		return nil, false
+	}
+
+	tp1, err := (&jose.JSONWebKey{Key: signer.Public()}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, false
+	}
+
+	tp2, err := (&jose.JSONWebKey{Key: b.key}).Thumbprint(crypto.SHA256)
+	if err != nil || !bytes.Equal(tp1, tp2) {
+		return nil, false
+	}
+
+	if b.CreatedAt.After(a.CreatedAt) {
+		a.CreatedAt = b.CreatedAt
+	}
+
+	return a, true
+}
+
+// inferAlgorithm return's the default sign algorithm of the given key type.
+func inferAlgorithm(key interface{}) string {
+	if v, ok := key.(crypto.Signer); ok {
+		key = v.Public()
+	}
+
+	switch v := key.(type) {
+	case []byte:
+		return jwt.HS256
+	case *rsa.PublicKey:
+		return jwt.RS256
+	case ed25519.PublicKey:
+		return jwt.EdDSA
+	case *ecdsa.PublicKey:
+		switch v.Curve {
+		case elliptic.P256():
+			return jwt.ES256
+		case elliptic.P384():
+			return jwt.ES384
+		case elliptic.P521():
+			return jwt.ES512
+		}
+	}
+
+	return ""
+}
+
+// canSign reports whether the key is a symmetric or private key.
+func canSign(key interface{}) bool {
+	switch key.(type) {
+	case []byte, crypto.Signer:
+		return true
+	}
+	return false
+}
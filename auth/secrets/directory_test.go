@@ -0,0 +1,309 @@
+package secrets
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/go-jose/go-jose.v2"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/opaque"
+)
+
+var (
+	_ jwt.SecretsKeeper    = (*Directory)(nil)
+	_ jwt.SecretsLister    = (*Directory)(nil)
+	_ opaque.SecretsKeeper = (*Directory)(nil)
+)
+
+func TestDirectory(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	writePEM(t, filepath.Join(dir, "rsa.pem"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	writeJWK(t, filepath.Join(dir, "ec.json"), jose.JSONWebKey{Key: ecKey.Public()})
+	writeFile(t, filepath.Join(dir, "README"), "ignored")
+
+	d, err := NewDirectory(dir)
+	require.NoError(t, err)
+
+	kids, err := d.KIDs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rsa", "ec"}, kids)
+	assert.Equal(t, "rsa", d.KID())
+
+	_, alg, err := d.Get("ec")
+	require.NoError(t, err)
+	assert.Equal(t, jwt.ES384, alg)
+
+	_, alg, err = d.Get("rsa")
+	require.NoError(t, err)
+	assert.Equal(t, jwt.RS256, alg)
+
+	_, _, err = d.Get("unknown")
+	assert.True(t, errors.Is(err, ErrInvalidKID))
+
+	// public and private keys not used by opaque tokens.
+	keys, err := d.Keys()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	info := auth.NewDefaultUser("test", "1", nil, nil)
+	tk, err := jwt.IssueAccessToken(info, d)
+	require.NoError(t, err)
+
+	got, _, err := jwt.GetAuthenticateFunc(d)(context.TODO(), nil, tk)
+	require.NoError(t, err)
+	assert.Equal(t, info.GetID(), got.GetID())
+}
+
+func TestDirectoryOptions(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "ec.pem"), "PRIVATE KEY", der)
+
+	tp, err := (&jose.JSONWebKey{Key: ecKey.Public()}).Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+	kid := base64.RawURLEncoding.EncodeToString(tp)
+
+	d, err := NewDirectory(dir, SetThumbprintKID(), SetKeyAlgorithm(kid, jwt.ES256))
+	require.NoError(t, err)
+	assert.Equal(t, kid, d.KID())
+}
+
+func TestDirectoryMarker(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	writeJWK(t, filepath.Join(dir, "a.jwk"), jose.JSONWebKey{Key: []byte("a-secret"), KeyID: "a", Algorithm: jwt.HS512})
+	writeJWK(t, filepath.Join(dir, "b.jwk"), jose.JSONWebKey{Key: []byte("b-secret"), KeyID: "b"})
+	writeFile(t, filepath.Join(dir, "current"), "a\n")
+
+	d, err := NewDirectory(dir, SetReloadInterval(0))
+	require.NoError(t, err)
+	assert.Equal(t, "a", d.KID())
+
+	keys, err := d.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a-secret"), []byte("b-secret")}, keys)
+
+	_, alg, err := d.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, jwt.HS512, alg)
+
+	// reload on change.
+	writeFile(t, filepath.Join(dir, "current"), "b")
+	touch(t, filepath.Join(dir, "current"))
+	assert.Equal(t, "b", d.KID())
+
+	// keep previous keys on invalid marker.
+	writeFile(t, filepath.Join(dir, "current"), "c")
+	touch(t, filepath.Join(dir, "current"))
+	assert.Equal(t, "b", d.KID())
+	assert.True(t, errors.Is(d.Reload(), ErrNoSigningKey))
+}
+
+func TestDirectoryTLSSecret(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour)}
+	leaf, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), ca)
+	require.NoError(t, err)
+	intermediate, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, ca.Public(), ca)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf})
+	chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediate})...)
+
+	tp, err := (&jose.JSONWebKey{Key: key.Public()}).Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+
+	table := []struct {
+		name string
+		opts []auth.Option
+		kid  string
+	}{
+		{
+			name: "it pair key and certificate by file name",
+			kid:  "tls",
+		},
+		{
+			name: "it pair key and certificate by thumbprint",
+			opts: []auth.Option{SetThumbprintKID()},
+			kid:  base64.RawURLEncoding.EncodeToString(tp),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tempDir(t)
+			defer os.RemoveAll(dir)
+
+			writeFile(t, filepath.Join(dir, "tls.crt"), string(chain))
+			writePEM(t, filepath.Join(dir, "tls.key"), "PRIVATE KEY", der)
+
+			d, err := NewDirectory(dir, tt.opts...)
+			require.NoError(t, err)
+
+			kids, err := d.KIDs()
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.kid}, kids)
+			assert.Equal(t, tt.kid, d.KID())
+
+			k, alg, err := d.Get(tt.kid)
+			require.NoError(t, err)
+			assert.Equal(t, jwt.ES256, alg)
+			assert.Equal(t, key, k)
+		})
+	}
+}
+
+func TestDirectoryModTimeTie(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	writeJWK(t, filepath.Join(dir, "b.jwk"), jose.JSONWebKey{Key: []byte("b-secret"), KeyID: "b"})
+	writeJWK(t, filepath.Join(dir, "a.jwk"), jose.JSONWebKey{Key: []byte("a-secret"), KeyID: "a"})
+
+	ts := time.Now()
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "a.jwk"), ts, ts))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "b.jwk"), ts, ts))
+
+	clock := authtest.NewFakeClock(time.Now())
+	d, err := NewDirectory(dir, SetClock(clock))
+	require.NoError(t, err)
+	assert.Equal(t, "b", d.KID())
+
+	// reload once the interval elapsed.
+	writeFile(t, filepath.Join(dir, "current"), "a")
+	assert.Equal(t, "b", d.KID())
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, "a", d.KID())
+}
+
+func TestDirectoryErrors(t *testing.T) {
+	table := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name:  "it return error when PEM is invalid",
+			files: map[string]string{"a.pem": "invalid"},
+		},
+		{
+			name:  "it return error when JWK is invalid",
+			files: map[string]string{"a.json": "invalid"},
+		},
+		{
+			name: "it return error when kid is duplicated",
+			files: map[string]string{
+				"a.json": `{"kty":"oct","k":"c2VjcmV0"}`,
+				"a.jwk":  `{"kty":"oct","k":"c2VjcmV0"}`,
+			},
+		},
+		{
+			name: "it return error when kid is duplicated by private key and unrelated public key",
+			files: map[string]string{
+				"a.key": ecPEM(t, "PRIVATE KEY"),
+				"a.pem": ecPEM(t, "PUBLIC KEY"),
+			},
+		},
+		{
+			name: "it return error when marker refer to unknown key",
+			files: map[string]string{
+				"a.json":  `{"kty":"oct","k":"c2VjcmV0"}`,
+				"current": "b",
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tempDir(t)
+			defer os.RemoveAll(dir)
+			for k, v := range tt.files {
+				writeFile(t, filepath.Join(dir, k), v)
+			}
+			_, err := NewDirectory(dir)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := NewDirectory("/does/not/exist")
+	assert.Error(t, err)
+}
+
+func ecPEM(t *testing.T, typ string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var der []byte
+	if typ == "PUBLIC KEY" {
+		der, err = x509.MarshalPKIXPublicKey(key.Public())
+	} else {
+		der, err = x509.MarshalPKCS8PrivateKey(key)
+	}
+
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	return dir
+}
+
+func touch(t *testing.T, path string) {
+	ts := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(path, ts, ts))
+}
+
+func writeFile(t *testing.T, path, data string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(data), 0600))
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	writeFile(t, path, string(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})))
+}
+
+func writeJWK(t *testing.T, path string, jwk jose.JSONWebKey) {
+	b, err := json.Marshal(jwk)
+	require.NoError(t, err)
+	writeFile(t, path, string(b))
+}
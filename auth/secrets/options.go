@@ -0,0 +1,113 @@
+package secrets
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+// SetRotationInterval sets the duration after which a new signing key generated.
+//
+// Default is 24h.
+func SetRotationInterval(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Rotating); ok {
+			r.interval = d
+		}
+	})
+}
+
+// SetOverlap sets the duration a retired key kept to parse tokens,
+// It should be at least the issued tokens lifetime.
+//
+// Default is 24h.
+func SetOverlap(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Rotating); ok {
+			r.overlap = d
+		}
+	})
+}
+
+// SetStore sets the store used to persist keys material.
+//
+// Default keys kept in-memory only.
+func SetStore(s Store) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Rotating); ok {
+			r.store = s
+		}
+	})
+}
+
+// SetRSAKeySize sets the generated RSA keys size in bits.
+//
+// Default is 2048.
+func SetRSAKeySize(bits int) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*Rotating); ok {
+			r.bits = bits
+		}
+	})
+}
+
+// SetReloadInterval sets the interval the directory keeper checks the files for changes,
+// Or the interval the rotating keeper reloads the keys from the store.
+//
+// Default is 1m.
+func SetReloadInterval(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		switch t := v.(type) {
+		case *Directory:
+			t.interval = d
+		case *Rotating:
+			t.reload = d
+		}
+	})
+}
+
+// SetClock sets the clock used to rotate, expire, and reload keys.
+//
+// Default is auth.SystemClock.
+func SetClock(c auth.Clock) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		switch t := v.(type) {
+		case *Directory:
+			t.clock = c
+		case *Rotating:
+			t.clock = c
+		}
+	})
+}
+
+// SetSigningKeyFile sets the name of the marker file,
+// holding the id of the key used to sign new tokens.
+//
+// Default is "current".
+func SetSigningKeyFile(name string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if dir, ok := v.(*Directory); ok {
+			dir.marker = name
+		}
+	})
+}
+
+// SetThumbprintKID derive the directory keeper keys ids from the keys SHA-256 thumbprint (RFC 7638),
+// instead of the files names.
+func SetThumbprintKID() auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if dir, ok := v.(*Directory); ok {
+			dir.thumbprint = true
+		}
+	})
+}
+
+// SetKeyAlgorithm declares the sign algorithm of the given key id,
+// instead of the inferred algorithm from the key type.
+func SetKeyAlgorithm(kid, alg string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if dir, ok := v.(*Directory); ok {
+			dir.algs[kid] = alg
+		}
+	})
+}
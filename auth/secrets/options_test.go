@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		expected interface{}
+		opt      auth.Option
+		value    func(r *Rotating) interface{}
+	}{
+		{
+			expected: time.Hour,
+			opt:      SetRotationInterval(time.Hour),
+			value:    func(r *Rotating) interface{} { return r.interval },
+		},
+		{
+			expected: time.Hour,
+			opt:      SetOverlap(time.Hour),
+			value:    func(r *Rotating) interface{} { return r.overlap },
+		},
+		{
+			expected: 4096,
+			opt:      SetRSAKeySize(4096),
+			value:    func(r *Rotating) interface{} { return r.bits },
+		},
+		{
+			expected: time.Hour,
+			opt:      SetReloadInterval(time.Hour),
+			value:    func(r *Rotating) interface{} { return r.reload },
+		},
+		{
+			expected: Store(new(testStore)),
+			opt:      SetStore(new(testStore)),
+			value:    func(r *Rotating) interface{} { return r.store },
+		},
+	}
+
+	for _, tt := range tests {
+		r := new(Rotating)
+		tt.opt.Apply(r)
+		require.Equal(t, tt.expected, tt.value(r))
+	}
+}
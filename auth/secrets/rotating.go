@@ -0,0 +1,458 @@
+// Package secrets provides secrets keepers,
+// to sign and parse jwt and opaque tokens.
+package secrets
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+)
+
+var (
+	// ErrInvalidKID is returned by keeper Get method,
+	// when the secret/key id not found or retired.
+	ErrInvalidKID = errors.New("secrets: Invalid KID")
+
+	// ErrStoreConflict is returned by Store Save method,
+	// when the persisted keys changed since loaded.
+	ErrStoreConflict = errors.New("secrets: Store keys changed since loaded")
+
+	// ErrNotHMAC is returned by keeper Keys method,
+	// when the keeper sign algorithm is not HMAC,
+	// as opaque tokens signed using HMAC secrets only.
+	ErrNotHMAC = errors.New("secrets: Opaque tokens require an HMAC algorithm")
+)
+
+// minReload is the minimum duration between two store loads,
+// triggered by an unknown kid.
+const minReload = time.Second
+
+var algorithms = map[string]struct{}{
+	jwt.HS256: {}, jwt.HS384: {}, jwt.HS512: {},
+	jwt.RS256: {}, jwt.RS384: {}, jwt.RS512: {},
+	jwt.PS256: {}, jwt.PS384: {}, jwt.PS512: {},
+	jwt.ES256: {}, jwt.ES384: {}, jwt.ES512: {},
+	jwt.EdDSA: {},
+}
+
+// Key represents a signing secret/key material.
+type Key struct {
+	// ID represent the secret/key id.
+	ID string
+	// Algorithm represent the corresponding sign algorithm.
+	Algorithm string
+	// Secret represent the raw secret for HMAC algorithms,
+	// Otherwise, the PKCS #8, ASN.1 DER private key.
+	Secret []byte
+	// CreatedAt represent when the key generated.
+	CreatedAt time.Time
+	// ExpiresAt represent when the key no longer used to parse tokens.
+	ExpiresAt time.Time
+}
+
+// Store is used to persist keys material,
+// Typically shared between multiple instances of the same service.
+//
+// The store versions the persisted keys,
+// so concurrent rotations from multiple instances does not overwrite each other.
+type Store interface {
+	// Load used to load all persisted keys and their version.
+	Load(ctx context.Context) ([]Key, int64, error)
+	// Save used to persist all keys, replacing the previously persisted keys,
+	// only if the persisted version still equal to the given version, and return's the new version.
+	// Otherwise, it return's ErrStoreConflict.
+	// Save must be atomic, e.g a compare-and-swap.
+	Save(ctx context.Context, keys []Key, version int64) (int64, error)
+}
+
+// Rotating implements jwt.SecretsKeeper, and opaque.SecretsKeeper for HMAC algorithms,
+// and generates a new signing key on schedule.
+//
+// Retired keys kept to parse tokens during an overlap window,
+// that should be at least the tokens lifetime.
+//
+// Keepers sharing the same store reloads the keys periodically (default 1m),
+// and when asked for an unknown kid, to pick up keys generated by other instances.
+type Rotating struct {
+	mu       sync.RWMutex
+	alg      string
+	bits     int
+	interval time.Duration
+	overlap  time.Duration
+	reload   time.Duration
+	store    Store
+	clock    auth.Clock
+	version  int64
+	checked  time.Time
+	keys     []*entry // newest first.
+}
+
+type entry struct {
+	Key
+	key  interface{}
+	file string
+}
+
+// NewRotating return's a new rotating secrets keeper,
+// generating keys for the given sign algorithm.
+//
+// Supported algorithms are HMAC, RSA, RSA-PSS, ECDSA, and EdDSA.
+func NewRotating(alg string, opts ...auth.Option) (*Rotating, error) {
+	r := &Rotating{
+		alg:      alg,
+		bits:     2048,
+		interval: time.Hour * 24,
+		overlap:  time.Hour * 24,
+		reload:   time.Minute,
+		clock:    auth.SystemClock,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(r)
+	}
+
+	if _, ok := algorithms[alg]; !ok {
+		return nil, errors.New("secrets: Unsupported algorithm " + alg)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	if r.due() {
+		if err := r.rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// KID return's the most recently generated secret/key id,
+// and rotates the key if it's due.
+//
+// If the rotation fails the current key used until the next attempt,
+// unless it's expired.
+func (r *Rotating) KID() string {
+	r.check(true)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.keys) == 0 || !r.keys[0].ExpiresAt.After(r.clock.Now()) {
+		return ""
+	}
+
+	return r.keys[0].ID
+}
+
+// KIDs return's all non expired secrets/keys ids.
+func (r *Rotating) KIDs() ([]string, error) {
+	r.check(false)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.clock.Now()
+	ids := make([]string, 0, len(r.keys))
+
+	for _, e := range r.keys {
+		if e.ExpiresAt.After(now) {
+			ids = append(ids, e.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// Get return's secret/key and the corresponding sign algorithm.
+//
+// An unknown kid reloads the keys from the store,
+// at most once per second.
+func (r *Rotating) Get(kid string) (interface{}, string, error) {
+	r.check(false)
+
+	if e := r.get(kid); e != nil {
+		return e.key, e.Algorithm, nil
+	}
+
+	r.mu.Lock()
+	if r.store != nil && r.clock.Now().Sub(r.checked) >= minReload {
+		_ = r.load()
+	}
+	r.mu.Unlock()
+
+	if e := r.get(kid); e != nil {
+		return e.key, e.Algorithm, nil
+	}
+
+	return nil, "", fmt.Errorf("%w %s", ErrInvalidKID, kid)
+}
+
+func (r *Rotating) get(kid string) *entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.clock.Now()
+
+	for _, e := range r.keys {
+		if e.ID == kid && e.ExpiresAt.After(now) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Keys return's all non expired secrets to sign and parse opaque token,
+// in descending order timestamp, and rotates the key if it's due.
+//
+// Keys return's ErrNotHMAC when the keeper sign algorithm is not HMAC,
+// to not use private keys material as HMAC secrets.
+func (r *Rotating) Keys() ([][]byte, error) {
+	if !isHMAC(r.alg) {
+		return nil, ErrNotHMAC
+	}
+
+	r.check(true)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.clock.Now()
+	keys := make([][]byte, 0, len(r.keys))
+
+	for _, e := range r.keys {
+		if e.ExpiresAt.After(now) && isHMAC(e.Algorithm) {
+			keys = append(keys, e.Secret)
+		}
+	}
+
+	return keys, nil
+}
+
+// Rotate generates a new signing key and retires the current one,
+// unless another keeper sharing the same store has recently rotated it.
+func (r *Rotating) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	if !r.due() {
+		return nil
+	}
+
+	return r.rotate()
+}
+
+// check rotates the key if it's due and rotate is true,
+// Otherwise, it reloads the keys from the store if the reload interval elapsed.
+func (r *Rotating) check(rotate bool) {
+	r.mu.RLock()
+	due := rotate && r.due()
+	stale := r.store != nil && r.clock.Now().Sub(r.checked) >= r.reload
+	r.mu.RUnlock()
+
+	switch {
+	case due:
+		_ = r.Rotate()
+	case stale:
+		r.mu.Lock()
+		_ = r.load()
+		r.mu.Unlock()
+	}
+}
+
+// due reports whether the most recently generated key must be rotated.
+func (r *Rotating) due() bool {
+	return len(r.keys) == 0 || !r.keys[0].CreatedAt.Add(r.interval).After(r.clock.Now())
+}
+
+func (r *Rotating) rotate() error {
+	key, err := generate(r.alg, r.bits)
+	if err != nil {
+		return err
+	}
+
+	secret, err := marshal(key)
+	if err != nil {
+		return err
+	}
+
+	id := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, id); err != nil {
+		return err
+	}
+
+	now := r.clock.Now().UTC()
+	e := &entry{
+		Key: Key{
+			ID:        base64.RawURLEncoding.EncodeToString(id),
+			Algorithm: r.alg,
+			Secret:    secret,
+			CreatedAt: now,
+			ExpiresAt: now.Add(r.interval + r.overlap),
+		},
+		key: key,
+	}
+
+	keys := append([]*entry{e}, r.keys...)
+
+	if r.store != nil {
+		version, err := r.store.Save(context.Background(), raw(keys), r.version)
+
+		if errors.Is(err, ErrStoreConflict) {
+			// another keeper sharing the same store saved first,
+			// use its keys unless the key still due.
+			if err := r.load(); err != nil {
+				return err
+			}
+
+			if r.due() {
+				return ErrStoreConflict
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		r.version = version
+	}
+
+	r.keys = keys
+	return nil
+}
+
+// load replaces the keeper keys with the persisted non expired keys.
+func (r *Rotating) load() error {
+	now := r.clock.Now()
+	r.checked = now
+
+	if r.store == nil {
+		r.keys = prune(r.keys, now)
+		return nil
+	}
+
+	keys, version, err := r.store.Load(context.Background())
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*entry, 0, len(keys))
+
+	for _, k := range keys {
+		key, err := unmarshal(k.Algorithm, k.Secret)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, &entry{Key: k, key: key})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+
+	r.keys = prune(entries, now)
+	r.version = version
+	return nil
+}
+
+func prune(entries []*entry, now time.Time) []*entry {
+	valid := entries[:0]
+	for _, e := range entries {
+		if e.ExpiresAt.After(now) {
+			valid = append(valid, e)
+		}
+	}
+	return valid
+}
+
+func raw(entries []*entry) []Key {
+	keys := make([]Key, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+// generate return's a new key for the given algorithm.
+func isHMAC(alg string) bool {
+	return strings.HasPrefix(alg, "HS")
+}
+
+func generate(alg string, bits int) (interface{}, error) {
+	switch alg {
+	case jwt.HS256:
+		return random(32)
+	case jwt.HS384:
+		return random(48)
+	case jwt.HS512:
+		return random(64)
+	case jwt.RS256, jwt.RS384, jwt.RS512, jwt.PS256, jwt.PS384, jwt.PS512:
+		return rsa.GenerateKey(rand.Reader, bits)
+	case jwt.ES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case jwt.ES384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case jwt.ES512:
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case jwt.EdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+
+	return nil, errors.New("secrets: Unsupported algorithm " + alg)
+}
+
+func random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func marshal(key interface{}) ([]byte, error) {
+	if b, ok := key.([]byte); ok {
+		return b, nil
+	}
+	return x509.MarshalPKCS8PrivateKey(key)
+}
+
+func unmarshal(alg string, secret []byte) (interface{}, error) {
+	switch alg {
+	case jwt.HS256, jwt.HS384, jwt.HS512:
+		return secret, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(secret)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: Failed to parse %s private key, %w", alg, err)
+	}
+
+	return key, nil
+}
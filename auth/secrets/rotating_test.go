@@ -0,0 +1,236 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/opaque"
+)
+
+var (
+	_ jwt.SecretsKeeper    = (*Rotating)(nil)
+	_ jwt.SecretsLister    = (*Rotating)(nil)
+	_ opaque.SecretsKeeper = (*Rotating)(nil)
+)
+
+func TestRotatingAlgorithms(t *testing.T) {
+	algs := []string{
+		jwt.HS256, jwt.HS384, jwt.HS512,
+		jwt.RS256, jwt.PS256,
+		jwt.ES256, jwt.ES384, jwt.ES512,
+		jwt.EdDSA,
+	}
+
+	info := auth.NewDefaultUser("test", "1", nil, nil)
+
+	for _, alg := range algs {
+		t.Run(alg, func(t *testing.T) {
+			store := new(testStore)
+			r, err := NewRotating(alg, SetStore(store))
+			require.NoError(t, err)
+
+			tk, err := jwt.IssueAccessToken(info, r)
+			require.NoError(t, err)
+
+			// a second keeper sharing the same store parse the token.
+			r2, err := NewRotating(alg, SetStore(store))
+			require.NoError(t, err)
+			assert.Equal(t, r.KID(), r2.KID())
+
+			fn := jwt.GetAuthenticateFunc(r2)
+			got, _, err := fn(context.TODO(), nil, tk)
+			require.NoError(t, err)
+			assert.Equal(t, info.GetID(), got.GetID())
+		})
+	}
+
+	_, err := NewRotating("none")
+	assert.Error(t, err)
+}
+
+func TestRotatingKeysNotHMAC(t *testing.T) {
+	for _, alg := range []string{jwt.RS256, jwt.ES256, jwt.EdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			r, err := NewRotating(alg)
+			require.NoError(t, err)
+
+			keys, err := r.Keys()
+			assert.Equal(t, ErrNotHMAC, err)
+			assert.Nil(t, keys)
+		})
+	}
+}
+
+func TestRotatingRotation(t *testing.T) {
+	clock := authtest.NewFakeClock(time.Now())
+	r, err := NewRotating(
+		jwt.HS256,
+		SetRotationInterval(time.Hour),
+		SetOverlap(time.Hour),
+		SetClock(clock),
+	)
+	require.NoError(t, err)
+
+	kid := r.KID()
+	clock.Advance(time.Hour)
+
+	next := r.KID()
+	assert.NotEqual(t, kid, next)
+
+	// retired key kept during the overlap window.
+	_, _, err = r.Get(kid)
+	assert.NoError(t, err)
+
+	keys, err := r.Keys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	ids, err := r.KIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{next, kid}, ids)
+
+	clock.Advance(time.Hour)
+
+	_, _, err = r.Get(kid)
+	assert.True(t, errors.Is(err, ErrInvalidKID))
+}
+
+func TestRotatingKeysRotation(t *testing.T) {
+	clock := authtest.NewFakeClock(time.Now())
+	r, err := NewRotating(jwt.HS256, SetRotationInterval(time.Hour), SetOverlap(time.Hour), SetClock(clock))
+	require.NoError(t, err)
+
+	keys, err := r.Keys()
+	require.NoError(t, err)
+
+	// an idle opaque signer never calls KID.
+	clock.Advance(time.Hour * 3)
+
+	next, err := r.Keys()
+	require.NoError(t, err)
+	assert.Len(t, next, 1)
+	assert.NotEqual(t, keys[0], next[0])
+}
+
+func TestRotatingExpiredKID(t *testing.T) {
+	clock := authtest.NewFakeClock(time.Now())
+	store := new(testStore)
+	r, err := NewRotating(jwt.HS256, SetStore(store), SetClock(clock))
+	require.NoError(t, err)
+
+	store.err = errors.New("store error")
+	clock.Advance(time.Hour * 24)
+	assert.NotEmpty(t, r.KID())
+
+	clock.Advance(time.Hour * 24)
+	assert.Empty(t, r.KID())
+}
+
+func TestRotatingReload(t *testing.T) {
+	clock := authtest.NewFakeClock(time.Now())
+	store := new(testStore)
+
+	r1, err := NewRotating(jwt.HS256, SetStore(store), SetClock(clock))
+	require.NoError(t, err)
+
+	r2, err := NewRotating(jwt.HS256, SetStore(store), SetClock(clock))
+	require.NoError(t, err)
+
+	clock.Advance(time.Hour * 24)
+	kid := r1.KID()
+
+	// unknown kid reloads the keys.
+	_, _, err = r2.Get(kid)
+	assert.NoError(t, err)
+
+	// unknown kid reload rate limited.
+	_, _, err = r2.Get("unknown")
+	assert.True(t, errors.Is(err, ErrInvalidKID))
+	keys, version, _ := store.Load(context.TODO())
+	k := Key{ID: "new", Algorithm: jwt.HS256, Secret: []byte("secret"), ExpiresAt: clock.Now().Add(time.Hour)}
+	_, err = store.Save(context.TODO(), append(keys, k), version)
+	require.NoError(t, err)
+
+	_, _, err = r2.Get("new")
+	assert.True(t, errors.Is(err, ErrInvalidKID))
+
+	// reload interval elapsed.
+	clock.Advance(time.Minute)
+	ids, err := r2.KIDs()
+	assert.NoError(t, err)
+	assert.Contains(t, ids, "new")
+}
+
+func TestRotatingStoreConflict(t *testing.T) {
+	clock := authtest.NewFakeClock(time.Now())
+	store := new(testStore)
+
+	r1, err := NewRotating(jwt.HS256, SetStore(store), SetClock(clock))
+	require.NoError(t, err)
+
+	r2, err := NewRotating(jwt.HS256, SetStore(store), SetClock(clock))
+	require.NoError(t, err)
+
+	clock.Advance(time.Hour * 24)
+
+	// r1 rotates between r2 load and save.
+	store.beforeSave = func() {
+		store.beforeSave = nil
+		require.NoError(t, r1.Rotate())
+	}
+
+	require.NoError(t, r2.Rotate())
+	assert.Equal(t, r1.KID(), r2.KID())
+	assert.Len(t, store.keys, 2)
+}
+
+func TestRotatingStoreError(t *testing.T) {
+	store := &testStore{err: errors.New("store error")}
+	_, err := NewRotating(jwt.HS256, SetStore(store))
+	assert.Error(t, err)
+}
+
+type testStore struct {
+	mu         sync.Mutex
+	err        error
+	keys       []Key
+	version    int64
+	beforeSave func()
+}
+
+func (s *testStore) Load(ctx context.Context) ([]Key, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys, s.version, s.err
+}
+
+func (s *testStore) Save(ctx context.Context, keys []Key, version int64) (int64, error) {
+	if s.beforeSave != nil {
+		s.beforeSave()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	if s.version != version {
+		return 0, ErrStoreConflict
+	}
+
+	s.keys = keys
+	s.version++
+
+	return s.version, nil
+}
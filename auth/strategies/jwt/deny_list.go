@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+// DenyList hold all revoked tokens ids (jti) until the tokens expire.
+type DenyList interface {
+	// Deny adds token id to the deny-list,
+	// The token id no longer needs to be denied after exp time,
+	// a zero exp denies the token id indefinitely.
+	Deny(jti string, exp time.Time) error
+	// IsDenied reports whether the token id has been denied.
+	IsDenied(jti string) (bool, error)
+}
+
+// MemoryDenyList implements the DenyList and holds revoked tokens ids in-memory,
+// entries are dropped once their tokens have expired.
+// The zero value is ready to use.
+type MemoryDenyList struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	clock   auth.Clock
+}
+
+// Deny adds token id to the deny-list until exp time,
+// or indefinitely when exp is zero.
+func (m *MemoryDenyList) Deny(jti string, exp time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	if m.entries == nil {
+		m.entries = make(map[string]time.Time)
+	}
+
+	for k, v := range m.entries {
+		if !v.IsZero() && !v.After(now) {
+			delete(m.entries, k)
+		}
+	}
+
+	if exp.IsZero() || exp.After(now) {
+		m.entries[jti] = exp
+	}
+
+	return nil
+}
+
+// IsDenied reports whether the token id has been denied and not yet expired.
+func (m *MemoryDenyList) IsDenied(jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	exp, ok := m.entries[jti]
+	if !ok {
+		return false, nil
+	}
+
+	if !exp.IsZero() && !exp.After(m.now()) {
+		delete(m.entries, jti)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (m *MemoryDenyList) now() time.Time {
+	if m.clock == nil {
+		return auth.SystemClock.Now()
+	}
+	return m.clock.Now()
+}
+
+// NewMemoryDenyList return's new in-memory deny-list.
+// Accepted options: SetClock.
+func NewMemoryDenyList(opts ...auth.Option) *MemoryDenyList {
+	m := &MemoryDenyList{
+		entries: make(map[string]time.Time),
+		clock:   auth.SystemClock,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(m)
+	}
+
+	return m
+}
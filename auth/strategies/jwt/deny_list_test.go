@@ -0,0 +1,130 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shaj13/libcache"
+	_ "github.com/shaj13/libcache/lru"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	ijwt "github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+)
+
+func TestMemoryDenyList(t *testing.T) {
+	clock := authtest.NewFakeClock(time.Now())
+	d := NewMemoryDenyList(SetClock(clock))
+
+	assert.NoError(t, d.Deny("valid", clock.Now().Add(time.Hour)))
+	assert.NoError(t, d.Deny("expired", clock.Now().Add(-time.Hour)))
+	assert.NoError(t, d.Deny("short", clock.Now().Add(time.Minute)))
+
+	denied, err := d.IsDenied("valid")
+	assert.NoError(t, err)
+	assert.True(t, denied)
+
+	denied, err = d.IsDenied("expired")
+	assert.NoError(t, err)
+	assert.False(t, denied)
+
+	denied, err = d.IsDenied("short")
+	assert.NoError(t, err)
+	assert.True(t, denied)
+
+	clock.Advance(time.Minute)
+
+	denied, err = d.IsDenied("short")
+	assert.NoError(t, err)
+	assert.False(t, denied)
+	assert.Len(t, d.entries, 1)
+}
+
+func TestMemoryDenyListZeroValue(t *testing.T) {
+	d := new(MemoryDenyList)
+
+	denied, err := d.IsDenied("valid")
+	assert.NoError(t, err)
+	assert.False(t, denied)
+
+	assert.NoError(t, d.Deny("valid", time.Now().Add(time.Hour)))
+
+	denied, err = d.IsDenied("valid")
+	assert.NoError(t, err)
+	assert.True(t, denied)
+
+	assert.NoError(t, d.Deny("forever", time.Time{}))
+
+	denied, err = d.IsDenied("forever")
+	assert.NoError(t, err)
+	assert.True(t, denied)
+}
+
+func TestRevoke(t *testing.T) {
+	s := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+	d := NewMemoryDenyList()
+	info := auth.NewDefaultUser("test", "test", nil, nil)
+
+	tk, err := IssueAccessToken(info, s)
+	assert.NoError(t, err)
+
+	strategy := New(libcache.LRU.New(0), s, SetDenyList(d))
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+tk)
+
+	_, err = strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	err = auth.Revoke(strategy, tk)
+	assert.NoError(t, err)
+
+	_, err = strategy.Authenticate(r.Context(), r)
+	assert.True(t, errors.Is(err, ErrRevokedToken))
+
+	err = auth.Revoke(strategy, 1)
+	assert.Error(t, err)
+
+	err = auth.Append(strategy, "token", info)
+	assert.NoError(t, err)
+}
+
+func TestRevokeWithoutExpiry(t *testing.T) {
+	s := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+	clock := authtest.NewFakeClock(time.Now())
+	d := NewMemoryDenyList(SetClock(clock))
+	info := auth.NewDefaultUser("test", "test", nil, nil)
+
+	tk, err := ijwt.IssueToken(s, info, claims.Standard{
+		Subject:  "test",
+		Audience: claims.StringOrList{""},
+		JWTID:    "no-exp",
+	})
+	assert.NoError(t, err)
+
+	strategy := New(libcache.LRU.New(0), s, SetDenyList(d), SetClock(clock))
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+tk)
+
+	_, err = strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	err = auth.Revoke(strategy, tk)
+	assert.NoError(t, err)
+
+	clock.Advance(time.Hour * 24 * 365)
+
+	_, err = strategy.Authenticate(r.Context(), r)
+	assert.True(t, errors.Is(err, ErrRevokedToken))
+}
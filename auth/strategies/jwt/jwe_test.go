@@ -0,0 +1,121 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+func TestTokenEncryption(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	sign := StaticSecret{ID: "sig", Secret: []byte("test-secret"), Algorithm: HS256}
+	info := auth.NewDefaultUser("test", "1", nil, auth.Extensions{"email": []string{"test@example.com"}})
+
+	table := []struct {
+		name      string
+		keeper    SecretsKeeper
+		issue     []auth.Option
+		parse     []auth.Option
+		issueErr  bool
+		parseErr  error
+		plaintext bool
+	}{
+		{
+			name:   "it issue and parse nested token using RSA-OAEP-256",
+			keeper: StaticSecret{ID: "enc", Secret: rsaKey, Algorithm: RSAOAEP256},
+		},
+		{
+			name:   "it issue and parse nested token using ECDH-ES+A256KW",
+			keeper: StaticSecret{ID: "enc", Secret: ecKey, Algorithm: ECDHESA256KW},
+			issue:  []auth.Option{SetContentEncryption(A128CBCHS256)},
+			parse:  []auth.Option{SetContentEncryption(A256GCM, A128CBCHS256)},
+		},
+		{
+			name:   "it issue and parse encrypted only token using dir",
+			keeper: StaticSecret{ID: "enc", Secret: make([]byte, 32), Algorithm: DIRECT},
+			issue:  []auth.Option{SetEncryptOnly()},
+			parse:  []auth.Option{SetEncryptOnly()},
+		},
+		{
+			name:   "it issue and parse encrypted only token using A256KW",
+			keeper: StaticSecret{ID: "enc", Secret: make([]byte, 32), Algorithm: A256KW},
+			issue:  []auth.Option{SetEncryptOnly()},
+			parse:  []auth.Option{SetEncryptOnly()},
+		},
+		{
+			name:     "it return error when encrypt only with asymmetric key management",
+			keeper:   StaticSecret{ID: "enc", Secret: rsaKey, Algorithm: RSAOAEP},
+			issue:    []auth.Option{SetEncryptOnly()},
+			issueErr: true,
+		},
+		{
+			name:     "it return error when key management algorithm not allowed",
+			keeper:   StaticSecret{ID: "enc", Secret: rsaKey, Algorithm: "RSA1_5"},
+			issueErr: true,
+		},
+		{
+			name:     "it return error when content encryption not allowed",
+			keeper:   StaticSecret{ID: "enc", Secret: rsaKey, Algorithm: RSAOAEP256},
+			issue:    []auth.Option{SetContentEncryption(A128GCM)},
+			parseErr: ErrUnsupportedAlg,
+		},
+		{
+			name:     "it return error when nested token parsed as encrypted only",
+			keeper:   StaticSecret{ID: "enc", Secret: make([]byte, 32), Algorithm: A256KW},
+			parse:    []auth.Option{SetEncryptOnly()},
+			parseErr: errors.New(""),
+		},
+		{
+			name:      "it return error when signed token parsed as encrypted",
+			keeper:    StaticSecret{ID: "enc", Secret: rsaKey, Algorithm: RSAOAEP256},
+			plaintext: true,
+			parseErr:  errors.New(""),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []auth.Option{SetEncryption(tt.keeper)}
+			if tt.plaintext {
+				opts = nil
+			}
+
+			str, err := IssueAccessToken(info, sign, append(opts, tt.issue...)...)
+			if tt.issueErr {
+				assert.True(t, errors.Is(err, ErrUnsupportedAlg), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotContains(t, str, "test@example.com")
+
+			fn := GetAuthenticateFunc(sign, append([]auth.Option{SetEncryption(tt.keeper)}, tt.parse...)...)
+			got, _, err := fn(context.TODO(), nil, str)
+
+			if tt.parseErr != nil {
+				assert.Error(t, err)
+				if errors.Is(tt.parseErr, ErrUnsupportedAlg) {
+					assert.True(t, errors.Is(err, ErrUnsupportedAlg), "got %v", err)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, info.GetID(), got.GetID())
+			assert.Equal(t, "test@example.com", got.GetExtensions().Get("email"))
+		})
+	}
+}
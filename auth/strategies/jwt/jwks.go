@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopkg.in/go-jose/go-jose.v2"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+// JWKSHandler return's http.Handler that publishes the public keys of the SecretsKeeper,
+// as a JWK Set defined in RFC 7517.
+// Typically used to expose the keys to other services,
+// verifying the issued tokens e.g. using oauth2/jwt strategy.
+//
+// All secrets/keys published when the SecretsKeeper implements SecretsLister,
+// Otherwise, only the most recently used key published.
+// HMAC secrets never published.
+func JWKSHandler(s SecretsKeeper, opts ...auth.Option) http.Handler {
+	h := new(jwksHandler)
+	h.keeper = s
+	h.maxAge = time.Minute * 5
+	for _, opt := range opts {
+		opt.Apply(h)
+	}
+	return h
+}
+
+type jwksHandler struct {
+	keeper SecretsKeeper
+	maxAge time.Duration
+}
+
+func (h *jwksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodHead)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	set, err := h.keySet()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	body, err := json.Marshal(set)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.maxAge.Seconds())))
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(body)
+	}
+}
+
+func (h *jwksHandler) keySet() (*jose.JSONWebKeySet, error) {
+	kids := []string{h.keeper.KID()}
+
+	if l, ok := h.keeper.(SecretsLister); ok {
+		ids, err := l.KIDs()
+		if err != nil {
+			return nil, err
+		}
+		kids = ids
+	}
+
+	set := &jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{},
+	}
+
+	for _, kid := range kids {
+		key, alg, err := h.keeper.Get(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		pub, ok := publicKey(key)
+		if !ok {
+			continue
+		}
+
+		set.Keys = append(set.Keys, jose.JSONWebKey{
+			Key:       pub,
+			KeyID:     kid,
+			Algorithm: alg,
+			Use:       "sig",
+		})
+	}
+
+	return set, nil
+}
+
+// publicKey return's the public key of the given asymmetric key,
+// and false if the key is symmetric or unsupported.
+func publicKey(key interface{}) (crypto.PublicKey, bool) {
+	if v, ok := key.(crypto.Signer); ok {
+		key = v.Public()
+	}
+
+	switch v := key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return v, true
+	case rsa.PublicKey:
+		return &v, true
+	case ecdsa.PublicKey:
+		return &v, true
+	default:
+		return nil, false
+	}
+}
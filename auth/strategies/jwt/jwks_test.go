@@ -0,0 +1,103 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/go-jose/go-jose.v2"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	k := testKeeper{
+		"rsa":  {key: rsaKey, alg: RS256},
+		"ec":   {key: ecKey, alg: ES256},
+		"ed":   {key: edKey, alg: EdDSA},
+		"hmac": {key: []byte("secret"), alg: HS256},
+	}
+
+	h := JWKSHandler(k, SetJWKSMaxAge(time.Hour))
+
+	r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+
+	set := new(jose.JSONWebKeySet)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), set))
+	assert.Len(t, set.Keys, 3)
+
+	for _, key := range set.Keys {
+		assert.True(t, key.IsPublic())
+		assert.Equal(t, "sig", key.Use)
+		assert.Equal(t, k[key.KeyID].alg, key.Algorithm)
+	}
+
+	assert.Empty(t, set.Key("hmac"))
+
+	r = httptest.NewRequest(http.MethodPost, "/.well-known/jwks.json", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestJWKSHandlerStaticSecret(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	s := StaticSecret{ID: "kid", Secret: key, Algorithm: ES256}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	JWKSHandler(s).ServeHTTP(w, r)
+
+	set := new(jose.JSONWebKeySet)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), set))
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, key.Public(), set.Keys[0].Key)
+}
+
+type testKey struct {
+	key interface{}
+	alg string
+}
+
+type testKeeper map[string]testKey
+
+func (k testKeeper) KID() string {
+	return "rsa"
+}
+
+func (k testKeeper) KIDs() ([]string, error) {
+	ids := []string{}
+	for id := range k {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (k testKeeper) Get(kid string) (interface{}, string, error) {
+	v, ok := k[kid]
+	if !ok {
+		return nil, "", errors.New("invalid kid")
+	}
+	return v.key, v.alg, nil
+}
@@ -25,6 +25,14 @@ func GetAuthenticateFunc(s SecretsKeeper, opts ...auth.Option) token.Authenticat
 			token.WithNamedScopes(info, c.Scope.Split()...)
 		}
 
+		if c.Confirmation != nil {
+			token.WithConfirmation(info, *c.Confirmation)
+		}
+
+		if c.ExpiresAt == nil {
+			return info, time.Time{}, nil
+		}
+
 		return info, time.Time(*c.ExpiresAt), nil
 	}
 }
@@ -36,7 +44,38 @@ func GetAuthenticateFunc(s SecretsKeeper, opts ...auth.Option) token.Authenticat
 // 		fn := jwt.GetAuthenticateFunc(secretsKeeper, opts...)
 // 		token.New(fn, cache, opts...)
 //
+// Except when a deny-list provided via SetDenyList,
+// auth.Revoke adds the token id to the deny-list in addition to evicting it from the cache.
 func New(c auth.Cache, s SecretsKeeper, opts ...auth.Option) auth.Strategy {
 	fn := GetAuthenticateFunc(s, opts...)
-	return token.New(fn, c, opts...)
+	st := token.New(fn, c, opts...)
+	t := newAccessToken(s, opts...)
+
+	if t.deny == nil {
+		return st
+	}
+
+	return &strategy{Strategy: st, t: t}
+}
+
+type strategy struct {
+	auth.Strategy
+	t *accessToken
+}
+
+func (s *strategy) Append(tk interface{}, info auth.Info) error {
+	return auth.Append(s.Strategy, tk, info)
+}
+
+func (s *strategy) Revoke(tk interface{}) error {
+	str, ok := tk.(string)
+	if !ok {
+		return auth.NewTypeError("strategies/jwt:", "str", tk)
+	}
+
+	if err := s.t.revoke(str); err != nil {
+		return err
+	}
+
+	return auth.Revoke(s.Strategy, tk)
 }
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
 )
 
 // SetAudience sets token audience(aud),
@@ -36,6 +39,36 @@ func SetExpDuration(d time.Duration) auth.Option {
 	})
 }
 
+// SetClock sets the clock used to issue and verify tokens,
+// and to compute the cached token ttl,
+// Or the clock used by MemoryDenyList and MemoryRefreshTokenStore to expire their entries.
+// Default auth.SystemClock.
+func SetClock(c auth.Clock) auth.Option {
+	tc := token.SetClock(c)
+	return auth.OptionFunc(func(v interface{}) {
+		switch t := v.(type) {
+		case *accessToken:
+			t.clock = c
+		case *MemoryDenyList:
+			t.clock = c
+		case *MemoryRefreshTokenStore:
+			t.clock = c
+		}
+		tc.Apply(v)
+	})
+}
+
+// SetLeeway sets the allowed clock skew when verifying token time claims,
+// and backdating issued tokens iat and nbf.
+// Default Value claims.DefaultLeeway.
+func SetLeeway(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.leeway = d
+		}
+	})
+}
+
 // SetNamedScopes sets the access token scopes,
 func SetNamedScopes(scp ...string) auth.Option {
 	return auth.OptionFunc(func(v interface{}) {
@@ -44,3 +77,165 @@ func SetNamedScopes(scp ...string) auth.Option {
 		}
 	})
 }
+
+// SetConfirmation sets the access token confirmation (cnf),
+// to bind the token to a proof-of-possession key.
+func SetConfirmation(cnf claims.Confirmation) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.cnf = &cnf
+		}
+	})
+}
+
+// SetDenyList sets the deny-list to hold revoked tokens ids,
+// Once set the token id (jti) verified against the deny-list on each parse,
+// and auth.Revoke adds the token id to the deny-list.
+func SetDenyList(d DenyList) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.deny = d
+		}
+	})
+}
+
+// SetRefreshExpDuration sets refresh token exp duartion,
+// rotated refresh tokens inherit their family expiry, thus the duration bounds the whole family.
+// Default Value 7 days.
+func SetRefreshExpDuration(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*refresher); ok {
+			r.exp = d
+		}
+	})
+}
+
+// SetJWKSMaxAge sets the JWKS handler Cache-Control max-age,
+// Default Value 5 min.
+func SetJWKSMaxAge(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*jwksHandler); ok {
+			h.maxAge = d
+		}
+	})
+}
+
+// SetInfoFields sets the user info fields embedded in the issued token,
+// fields are the info JSON keys e.g Name, ID, Groups, and Extensions for auth.DefaultUser.
+// The token subject (sub) used as the user id, if the user id not embedded.
+//
+// Default all user info fields embedded.
+func SetInfoFields(fields ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.fields = make(map[string]struct{})
+			for _, f := range fields {
+				t.fields[f] = struct{}{}
+			}
+		}
+	})
+}
+
+// SetCustomClaims sets the function that return's custom private claims embedded in the issued token.
+// Custom claims can not override the registered claims e.g exp, sub.
+func SetCustomClaims(fn ClaimsFunc) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.claims = fn
+		}
+	})
+}
+
+// SetClaimResolver sets the jwt strategy ClaimResolver
+// to resolve the user info and custom private claims from the parsed token.
+// Default: auth.NewUserInfo
+func SetClaimResolver(c oauth2.ClaimsResolver) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.cr = c
+		}
+	})
+}
+
+// SetEncryption sets the secrets keeper that hold the keys to encrypt and decrypt tokens,
+// the keeper keys algorithms are the key management algorithms e.g RSA-OAEP-256, ECDH-ES, A256KW, dir.
+// Once set, tokens are signed then encrypted (nested JWT) on issue,
+// and only encrypted tokens are accepted on parse.
+//
+// RSA1_5 and PBES2 key management algorithms are not allowed.
+func SetEncryption(k SecretsKeeper) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			if t.enc == nil {
+				t.enc = new(encryption)
+			}
+			t.enc.keeper = k
+		}
+	})
+}
+
+// SetContentEncryption sets the allowed content encryption algorithms,
+// the first one used to issue tokens.
+// Default A256GCM.
+//
+// SetContentEncryption take effect only when SetEncryption provided.
+func SetContentEncryption(enc string, allowed ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			if t.enc == nil {
+				t.enc = new(encryption)
+			}
+			t.enc.encs = append([]string{enc}, allowed...)
+		}
+	})
+}
+
+// SetEncryptOnly issue and parse encrypted tokens without the nested signature,
+// the token integrity relies on the authenticated encryption, hence
+// only symmetric key management algorithms are allowed e.g A256KW, A256GCMKW, dir.
+//
+// SetEncryptOnly take effect only when SetEncryption provided.
+func SetEncryptOnly() auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			if t.enc == nil {
+				t.enc = new(encryption)
+			}
+			t.enc.only = true
+		}
+	})
+}
+
+// SetClientID sets the access token client_id claim,
+// the client to which the token issued.
+func SetClientID(id string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.client = id
+		}
+	})
+}
+
+// SetAccessTokenProfile issue and parse tokens following
+// the JWT profile for OAuth 2.0 access tokens as defined in RFC 9068.
+// Once set, the token typ header is at+jwt,
+// and the iss, exp, aud, sub, client_id, iat, and jti claims are required.
+//
+// SetAccessTokenProfile must be used with SetIssuer, SetAudience, and SetClientID.
+func SetAccessTokenProfile() auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.profile = true
+		}
+	})
+}
+
+// SetSigningPolicy sets the policy to verify the token signing algorithm and key on each parse,
+// a rejection reported as PolicyError.
+func SetSigningPolicy(p SigningPolicy) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*accessToken); ok {
+			t.policy = &p
+		}
+	})
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
 )
 
 func TestSetAudience(t *testing.T) {
@@ -25,3 +28,70 @@ func TestSetExpDuration(t *testing.T) {
 	tk := newAccessToken(nil, opt)
 	assert.Equal(t, time.Hour, tk.dur)
 }
+
+func TestSetClock(t *testing.T) {
+	clock := auth.ClockFunc(time.Now)
+	opt := SetClock(clock)
+	tk := newAccessToken(nil, opt)
+	assert.NotNil(t, tk.clock)
+}
+
+func TestSetLeeway(t *testing.T) {
+	opt := SetLeeway(time.Second)
+	tk := newAccessToken(nil, opt)
+	assert.Equal(t, time.Second, tk.leeway)
+}
+
+func TestSetConfirmation(t *testing.T) {
+	cnf := claims.Confirmation{JWKThumbprint: "test"}
+	opt := SetConfirmation(cnf)
+	tk := newAccessToken(nil, opt)
+	assert.Equal(t, &cnf, tk.cnf)
+}
+
+func TestSetInfoFields(t *testing.T) {
+	opt := SetInfoFields("ID")
+	tk := newAccessToken(nil, opt)
+	assert.Equal(t, map[string]struct{}{"ID": {}}, tk.fields)
+}
+
+func TestSetCustomClaims(t *testing.T) {
+	opt := SetCustomClaims(func(auth.Info) (interface{}, error) { return nil, nil })
+	tk := newAccessToken(nil, opt)
+	assert.NotNil(t, tk.claims)
+}
+
+func TestSetClaimResolver(t *testing.T) {
+	opt := SetClaimResolver(new(tenantClaims))
+	tk := newAccessToken(nil, opt)
+	assert.Equal(t, new(tenantClaims), tk.cr)
+}
+
+func TestSetEncryption(t *testing.T) {
+	k := StaticSecret{}
+	tk := newAccessToken(nil, SetEncryption(k), SetContentEncryption(A128GCM, A256GCM), SetEncryptOnly())
+	assert.Equal(t, &encryption{keeper: k, encs: []string{A128GCM, A256GCM}, only: true}, tk.enc)
+
+	// encryption disabled without keeper.
+	tk = newAccessToken(nil, SetEncryptOnly())
+	assert.Nil(t, tk.enc)
+
+	tk = newAccessToken(nil, SetEncryption(k))
+	assert.Equal(t, []string{A256GCM}, tk.enc.encs)
+}
+
+func TestSetClientID(t *testing.T) {
+	tk := newAccessToken(nil, SetClientID("client"))
+	assert.Equal(t, "client", tk.client)
+}
+
+func TestSetAccessTokenProfile(t *testing.T) {
+	tk := newAccessToken(nil, SetAccessTokenProfile())
+	assert.True(t, tk.profile)
+}
+
+func TestSetSigningPolicy(t *testing.T) {
+	p := SigningPolicy{AsymmetricOnly: true}
+	tk := newAccessToken(nil, SetSigningPolicy(p))
+	assert.Equal(t, &p, tk.policy)
+}
@@ -0,0 +1,36 @@
+package jwt
+
+import (
+	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+)
+
+// SigningPolicy represents jwt token signature verification policy,
+// that allows only listed algorithms, sets minimum RSA/EC key sizes,
+// requires the key type to match the algorithm family, and refuse HMAC keys if needed.
+//
+//	jwt.SetSigningPolicy(jwt.SigningPolicy{
+//		Algorithms:     []string{jwt.RS256, jwt.ES256},
+//		AsymmetricOnly: true,
+//		MinRSAKeySize:  2048,
+//		MinECKeySize:   256,
+//	})
+type SigningPolicy = jwt.Policy
+
+// PolicyError results when the token signing algorithm or key rejected by the signing policy.
+type PolicyError = jwt.PolicyError
+
+// PolicyReason represents signing policy rejection reason.
+type PolicyReason = jwt.PolicyReason
+
+const (
+	// AlgorithmNotAllowed results when the token signing algorithm,
+	// is unsupported or not one of the policy allowed algorithms.
+	AlgorithmNotAllowed = jwt.AlgorithmNotAllowed
+	// HMACNotAllowed results when the token signed using HMAC algorithm,
+	// and the policy allows only asymmetric algorithms.
+	HMACNotAllowed = jwt.HMACNotAllowed
+	// KeyTypeMismatch results when the key type does not match the signing algorithm family.
+	KeyTypeMismatch = jwt.KeyTypeMismatch
+	// WeakKey results when the key size is less than the policy minimum size.
+	WeakKey = jwt.WeakKey
+)
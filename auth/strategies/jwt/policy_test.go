@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+func TestSigningPolicy(t *testing.T) {
+	rsa2048, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	rsa1024, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	info := auth.NewDefaultUser("test", "1", nil, nil)
+
+	table := []struct {
+		name   string
+		issue  StaticSecret
+		parse  StaticSecret
+		policy SigningPolicy
+		reason *PolicyReason
+	}{
+		{
+			name:   "it accept token signed with allowed algorithm and key",
+			issue:  StaticSecret{ID: "kid", Secret: rsa2048, Algorithm: RS256},
+			policy: SigningPolicy{Algorithms: []string{RS256}, MinRSAKeySize: 2048, AsymmetricOnly: true},
+		},
+		{
+			name:   "it accept key without algorithm using token alg header",
+			issue:  StaticSecret{ID: "kid", Secret: p256, Algorithm: ES256},
+			parse:  StaticSecret{ID: "kid", Secret: p256},
+			policy: SigningPolicy{Algorithms: []string{ES256}},
+		},
+		{
+			name:   "it reject algorithm not in allowlist",
+			issue:  StaticSecret{ID: "kid", Secret: rsa2048, Algorithm: RS256},
+			policy: SigningPolicy{Algorithms: []string{ES256}},
+			reason: reason(AlgorithmNotAllowed),
+		},
+		{
+			name:   "it reject HMAC when asymmetric only",
+			issue:  StaticSecret{ID: "kid", Secret: make([]byte, 32), Algorithm: HS256},
+			policy: SigningPolicy{AsymmetricOnly: true},
+			reason: reason(HMACNotAllowed),
+		},
+		{
+			name:   "it reject short HMAC secret",
+			issue:  StaticSecret{ID: "kid", Secret: []byte("short"), Algorithm: HS256},
+			reason: reason(WeakKey),
+		},
+		{
+			name:   "it reject weak RSA key",
+			issue:  StaticSecret{ID: "kid", Secret: rsa1024, Algorithm: RS256},
+			policy: SigningPolicy{MinRSAKeySize: 2048},
+			reason: reason(WeakKey),
+		},
+		{
+			name:   "it reject key type mismatch",
+			issue:  StaticSecret{ID: "kid", Secret: rsa2048, Algorithm: RS256},
+			parse:  StaticSecret{ID: "kid", Secret: p256},
+			reason: reason(KeyTypeMismatch),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			str, err := IssueAccessToken(info, tt.issue)
+			require.NoError(t, err)
+
+			parse := tt.parse
+			if parse.Secret == nil {
+				parse = tt.issue
+			}
+
+			_, _, err = GetAuthenticateFunc(parse, SetSigningPolicy(tt.policy))(context.TODO(), nil, str)
+			if tt.reason == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			perr := PolicyError{}
+			require.True(t, errors.As(err, &perr), "got %v", err)
+			assert.Equal(t, *tt.reason, perr.Reason)
+		})
+	}
+}
+
+func reason(r PolicyReason) *PolicyReason {
+	return &r
+}
@@ -0,0 +1,265 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+var (
+	// ErrRefreshTokenNotFound is returned by refresh token store,
+	// when the refresh token not found in the store.
+	ErrRefreshTokenNotFound = errors.New("strategies/jwt: Refresh token does not exists")
+
+	// ErrRefreshTokenExpired is returned by RefreshTokens function,
+	// when the refresh token has expired.
+	ErrRefreshTokenExpired = errors.New("strategies/jwt: Refresh token has expired")
+
+	// ErrRefreshTokenReused is returned by RefreshTokens function,
+	// when an already used refresh token presented again,
+	// the whole refresh token family revoked in that case.
+	ErrRefreshTokenReused = errors.New("strategies/jwt: Refresh token has been reused")
+)
+
+// RefreshToken represent a refresh token entry in refresh token store.
+type RefreshToken struct {
+	// Signature a unique SHA-256 hash, per refresh token.
+	//
+	// Store the signature in plaintext without
+	// any form of obfuscation or encryption.
+	Signature string
+	// Family represent the id of refresh tokens chain,
+	// rotated refresh tokens share the same family as their ancestor.
+	Family string
+	// Lifespan represent when the refresh token expires.
+	Lifespan time.Time
+	// Used reports whether the refresh token has been exchanged.
+	Used bool
+	// Info represent auth info refresh token is mapped to it.
+	Info auth.Info
+}
+
+// RefreshTokenStore is used to manage refresh tokens.
+type RefreshTokenStore interface {
+	// Store used to store a new refresh token entry.
+	Store(ctx context.Context, t RefreshToken) error
+	// Consume used to atomically mark refresh token entry as used by its signature,
+	// It returns the entry as it was before being consumed.
+	Consume(ctx context.Context, signature string) (RefreshToken, error)
+	// RevokeFamily used to delete all refresh tokens entries of the given family.
+	RevokeFamily(ctx context.Context, family string) error
+}
+
+// IssueTokens issue jwt access token and a refresh token of a new family for the provided user info.
+func IssueTokens(
+	ctx context.Context,
+	info auth.Info,
+	k SecretsKeeper,
+	s RefreshTokenStore,
+	opts ...auth.Option,
+) (string, string, error) {
+	family, err := randomString(32)
+	if err != nil {
+		return "", "", fmt.Errorf("strategies/jwt: %w", err)
+	}
+
+	r := newRefresher(k, s, opts...)
+	return r.issue(ctx, info, family, r.token.clock.Now().Add(r.exp))
+}
+
+// RefreshTokens exchange the given refresh token with a fresh access and refresh tokens pair.
+// Refresh tokens are single-use, the exchanged refresh token marked as used,
+// and reusing it revokes its whole family.
+// The fresh refresh token expires along with its family, regardless of how often it rotated.
+func RefreshTokens(
+	ctx context.Context,
+	refreshToken string,
+	k SecretsKeeper,
+	s RefreshTokenStore,
+	opts ...auth.Option,
+) (string, string, error) {
+	return newRefresher(k, s, opts...).refresh(ctx, refreshToken)
+}
+
+type refresher struct {
+	store RefreshTokenStore
+	token *accessToken
+	exp   time.Duration
+}
+
+func (r *refresher) issue(
+	ctx context.Context,
+	info auth.Info,
+	family string,
+	lifespan time.Time,
+) (string, string, error) {
+	at, err := r.token.issue(info)
+	if err != nil {
+		return "", "", err
+	}
+
+	rt, err := randomString(32)
+	if err != nil {
+		return "", "", fmt.Errorf("strategies/jwt: %w", err)
+	}
+
+	t := RefreshToken{
+		Signature: signature(rt),
+		Family:    family,
+		Lifespan:  lifespan,
+		Info:      info,
+	}
+
+	if err := r.store.Store(ctx, t); err != nil {
+		return "", "", err
+	}
+
+	return at, rt, nil
+}
+
+func (r *refresher) refresh(ctx context.Context, rt string) (string, string, error) {
+	t, err := r.store.Consume(ctx, signature(rt))
+	if err != nil {
+		return "", "", err
+	}
+
+	// expiry checked first, a retried expired token is not a reuse.
+	if t.Lifespan.Before(r.token.clock.Now()) {
+		return "", "", ErrRefreshTokenExpired
+	}
+
+	if t.Used {
+		if err := r.store.RevokeFamily(ctx, t.Family); err != nil {
+			return "", "", err
+		}
+		return "", "", ErrRefreshTokenReused
+	}
+
+	return r.issue(ctx, t.Info, t.Family, t.Lifespan)
+}
+
+func newRefresher(k SecretsKeeper, s RefreshTokenStore, opts ...auth.Option) *refresher {
+	r := new(refresher)
+	r.store = s
+	r.token = newAccessToken(k, opts...)
+	r.exp = time.Hour * 24 * 7
+	for _, opt := range opts {
+		opt.Apply(r)
+	}
+	return r
+}
+
+func signature(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// MemoryRefreshTokenStore implements the RefreshTokenStore and holds refresh tokens in-memory,
+// expired entries are dropped on each store.
+// The zero value is ready to use.
+type MemoryRefreshTokenStore struct {
+	mu       sync.Mutex
+	tokens   map[string]RefreshToken
+	families map[string]map[string]struct{}
+	clock    auth.Clock
+}
+
+// Store stores a new refresh token entry.
+func (m *MemoryRefreshTokenStore) Store(_ context.Context, t RefreshToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tokens == nil {
+		m.tokens = make(map[string]RefreshToken)
+		m.families = make(map[string]map[string]struct{})
+	}
+
+	now := auth.SystemClock.Now()
+	if m.clock != nil {
+		now = m.clock.Now()
+	}
+
+	for k, v := range m.tokens {
+		if v.Lifespan.Before(now) {
+			m.delete(k, v.Family)
+		}
+	}
+
+	if _, ok := m.families[t.Family]; !ok {
+		m.families[t.Family] = make(map[string]struct{})
+	}
+
+	m.tokens[t.Signature] = t
+	m.families[t.Family][t.Signature] = struct{}{}
+
+	return nil
+}
+
+// Consume marks refresh token entry as used and returns it as it was before being consumed.
+func (m *MemoryRefreshTokenStore) Consume(_ context.Context, signature string) (RefreshToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.tokens[signature]
+	if !ok {
+		return RefreshToken{}, ErrRefreshTokenNotFound
+	}
+
+	used := t
+	used.Used = true
+	m.tokens[signature] = used
+
+	return t, nil
+}
+
+// RevokeFamily deletes all refresh tokens entries of the given family.
+func (m *MemoryRefreshTokenStore) RevokeFamily(_ context.Context, family string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k := range m.families[family] {
+		m.delete(k, family)
+	}
+
+	return nil
+}
+
+func (m *MemoryRefreshTokenStore) delete(signature, family string) {
+	delete(m.tokens, signature)
+	delete(m.families[family], signature)
+	if len(m.families[family]) == 0 {
+		delete(m.families, family)
+	}
+}
+
+// NewMemoryRefreshTokenStore return's new in-memory refresh token store.
+// Accepted options: SetClock.
+func NewMemoryRefreshTokenStore(opts ...auth.Option) *MemoryRefreshTokenStore {
+	m := &MemoryRefreshTokenStore{
+		tokens:   make(map[string]RefreshToken),
+		families: make(map[string]map[string]struct{}),
+		clock:    auth.SystemClock,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(m)
+	}
+
+	return m
+}
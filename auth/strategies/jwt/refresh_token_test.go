@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+)
+
+func TestRefreshTokens(t *testing.T) {
+	ctx := context.TODO()
+	info := auth.NewDefaultUser("test", "test", nil, nil)
+	k := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+	s := NewMemoryRefreshTokenStore()
+
+	at, rt, err := IssueTokens(ctx, info, k, s)
+	require.NoError(t, err)
+	assert.NotEmpty(t, at)
+	assert.NotEmpty(t, rt)
+
+	at2, rt2, err := RefreshTokens(ctx, rt, k, s)
+	require.NoError(t, err)
+	assert.NotEqual(t, rt, rt2)
+
+	_, u, err := newAccessToken(k).parse(at2)
+	require.NoError(t, err)
+	assert.Equal(t, info.GetID(), u.GetID())
+
+	// reuse the spent refresh token revoke the whole family.
+	_, _, err = RefreshTokens(ctx, rt, k, s)
+	assert.Equal(t, ErrRefreshTokenReused, err)
+
+	_, _, err = RefreshTokens(ctx, rt2, k, s)
+	assert.Equal(t, ErrRefreshTokenNotFound, err)
+	assert.Empty(t, s.tokens)
+	assert.Empty(t, s.families)
+}
+
+func TestRefreshTokensExpired(t *testing.T) {
+	ctx := context.TODO()
+	info := auth.NewDefaultUser("test", "test", nil, nil)
+	k := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+	s := NewMemoryRefreshTokenStore()
+
+	_, rt, err := IssueTokens(ctx, info, k, s, SetRefreshExpDuration(-time.Minute))
+	require.NoError(t, err)
+
+	_, _, err = RefreshTokens(ctx, rt, k, s)
+	assert.Equal(t, ErrRefreshTokenExpired, err)
+
+	_, _, err = RefreshTokens(ctx, "unknown", k, s)
+	assert.Equal(t, ErrRefreshTokenNotFound, err)
+}
+
+func TestRefreshTokensFamilyExpiry(t *testing.T) {
+	ctx := context.TODO()
+	clock := authtest.NewFakeClock(time.Now())
+	info := auth.NewDefaultUser("test", "test", nil, nil)
+	k := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+	s := NewMemoryRefreshTokenStore(SetClock(clock))
+	opts := []auth.Option{SetClock(clock), SetRefreshExpDuration(time.Hour)}
+
+	_, rt, err := IssueTokens(ctx, info, k, s, opts...)
+	require.NoError(t, err)
+
+	// keep rotating, the family still expires an hour after it issued.
+	for i := 0; i < 3; i++ {
+		clock.Advance(time.Minute * 15)
+		_, rt, err = RefreshTokens(ctx, rt, k, s, opts...)
+		require.NoError(t, err)
+	}
+
+	clock.Advance(time.Minute * 16)
+	_, _, err = RefreshTokens(ctx, rt, k, s, opts...)
+	assert.Equal(t, ErrRefreshTokenExpired, err)
+
+	// retrying the expired refresh token is not a reuse.
+	_, _, err = RefreshTokens(ctx, rt, k, s, opts...)
+	assert.Equal(t, ErrRefreshTokenExpired, err)
+}
+
+func TestMemoryRefreshTokenStore(t *testing.T) {
+	ctx := context.TODO()
+	clock := authtest.NewFakeClock(time.Now())
+	s := NewMemoryRefreshTokenStore(SetClock(clock))
+
+	_ = s.Store(ctx, RefreshToken{Signature: "1", Family: "a", Lifespan: clock.Now().Add(time.Minute)})
+
+	clock.Advance(time.Minute * 2)
+	_ = s.Store(ctx, RefreshToken{Signature: "2", Family: "b", Lifespan: clock.Now().Add(time.Hour)})
+
+	_, err := s.Consume(ctx, "1")
+	assert.Equal(t, ErrRefreshTokenNotFound, err)
+
+	got, err := s.Consume(ctx, "2")
+	assert.NoError(t, err)
+	assert.False(t, got.Used)
+
+	got, err = s.Consume(ctx, "2")
+	assert.NoError(t, err)
+	assert.True(t, got.Used)
+}
+
+func TestMemoryRefreshTokenStoreZeroValue(t *testing.T) {
+	ctx := context.TODO()
+	s := new(MemoryRefreshTokenStore)
+
+	_, err := s.Consume(ctx, "1")
+	assert.Equal(t, ErrRefreshTokenNotFound, err)
+	assert.NoError(t, s.RevokeFamily(ctx, "a"))
+	assert.NoError(t, s.Store(ctx, RefreshToken{Signature: "1", Family: "a", Lifespan: time.Now().Add(time.Hour)}))
+
+	got, err := s.Consume(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", got.Family)
+}
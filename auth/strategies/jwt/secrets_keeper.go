@@ -14,6 +14,13 @@ type SecretsKeeper interface {
 	Get(kid string) (key interface{}, algorithm string, err error)
 }
 
+// SecretsLister is an optional interface that may be implemented by a SecretsKeeper,
+// to enumerate all secrets/keys ids, including the retired ones still used to parse tokens.
+type SecretsLister interface {
+	// KIDs return's all secrets/keys ids.
+	KIDs() ([]string, error)
+}
+
 // StaticSecret implements the SecretsKeeper and holds only a single secret.
 type StaticSecret struct {
 	Secret    interface{}
@@ -26,6 +33,11 @@ func (s StaticSecret) KID() string {
 	return s.ID
 }
 
+// KIDs return's secret/key id.
+func (s StaticSecret) KIDs() ([]string, error) {
+	return []string{s.ID}, nil
+}
+
 // Get return's secret/key and the corresponding sign algorithm.
 func (s StaticSecret) Get(kid string) (key interface{}, algorithm string, err error) {
 	if kid != s.ID {
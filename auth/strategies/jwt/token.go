@@ -1,12 +1,15 @@
 package jwt
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/claims"
 	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
 )
 
 const (
@@ -38,7 +41,59 @@ const (
 	PS512 = "PS512"
 )
 
+const (
+	// RSAOAEP key management algorithm -- RSA-OAEP-SHA1.
+	RSAOAEP = "RSA-OAEP"
+	// RSAOAEP256 key management algorithm -- RSA-OAEP-SHA256.
+	RSAOAEP256 = "RSA-OAEP-256"
+	// A128KW key management algorithm -- AES key wrap (128).
+	A128KW = "A128KW"
+	// A192KW key management algorithm -- AES key wrap (192).
+	A192KW = "A192KW"
+	// A256KW key management algorithm -- AES key wrap (256).
+	A256KW = "A256KW"
+	// DIRECT key management algorithm -- Direct encryption.
+	DIRECT = "dir"
+	// ECDHES key management algorithm -- ECDH-ES.
+	ECDHES = "ECDH-ES"
+	// ECDHESA128KW key management algorithm -- ECDH-ES + AES key wrap (128).
+	ECDHESA128KW = "ECDH-ES+A128KW"
+	// ECDHESA192KW key management algorithm -- ECDH-ES + AES key wrap (192).
+	ECDHESA192KW = "ECDH-ES+A192KW"
+	// ECDHESA256KW key management algorithm -- ECDH-ES + AES key wrap (256).
+	ECDHESA256KW = "ECDH-ES+A256KW"
+	// A128GCMKW key management algorithm -- AES-GCM key wrap (128).
+	A128GCMKW = "A128GCMKW"
+	// A192GCMKW key management algorithm -- AES-GCM key wrap (192).
+	A192GCMKW = "A192GCMKW"
+	// A256GCMKW key management algorithm -- AES-GCM key wrap (256).
+	A256GCMKW = "A256GCMKW"
+)
+
+const (
+	// A128CBCHS256 content encryption algorithm -- AES-CBC + HMAC-SHA256 (128).
+	A128CBCHS256 = "A128CBC-HS256"
+	// A192CBCHS384 content encryption algorithm -- AES-CBC + HMAC-SHA384 (192).
+	A192CBCHS384 = "A192CBC-HS384"
+	// A256CBCHS512 content encryption algorithm -- AES-CBC + HMAC-SHA512 (256).
+	A256CBCHS512 = "A256CBC-HS512"
+	// A128GCM content encryption algorithm -- AES-GCM (128).
+	A128GCM = "A128GCM"
+	// A192GCM content encryption algorithm -- AES-GCM (192).
+	A192GCM = "A192GCM"
+	// A256GCM content encryption algorithm -- AES-GCM (256).
+	A256GCM = "A256GCM"
+)
+
 var (
+	// ErrRevokedToken is returned by Authenticate Strategy method,
+	// when the token id (jti) found in the deny-list.
+	ErrRevokedToken = errors.New("strategies/jwt: Token has been revoked")
+
+	// ErrMissingJTI is returned by Revoke function,
+	// when the token does not carry an id (jti) to be added to the deny-list.
+	ErrMissingJTI = errors.New("strategies/jwt: Token missing jti claim")
+
 	// ErrMissingKID is returned by Authenticate Strategy method,
 	// when failed to retrieve kid from token header.
 	ErrMissingKID = jwt.ErrMissingKID
@@ -46,36 +101,104 @@ var (
 	// ErrInvalidAlg is returned by Authenticate Strategy method,
 	// when jwt token alg header does not match key algorithm.
 	ErrInvalidAlg = jwt.ErrInvalidAlg
+
+	// ErrUnsupportedAlg is returned by Authenticate Strategy method,
+	// when jwt token key management or content encryption algorithm not allowed.
+	ErrUnsupportedAlg = jwt.ErrUnsupportedAlg
+
+	// ErrInvalidType is returned by Authenticate Strategy method,
+	// when jwt token typ header does not match the expected type e.g. at+jwt.
+	ErrInvalidType = jwt.ErrInvalidType
+
+	// ErrMissingClaim is returned by IssueAccessToken function and Authenticate Strategy method,
+	// when the access token profile enabled and the token missing a required claim.
+	ErrMissingClaim = jwt.ErrMissingClaim
+
+	// ErrMissingAudience is returned by IssueAccessToken function and Authenticate Strategy method,
+	// when the access token profile enabled and the audience not configured.
+	ErrMissingAudience = jwt.ErrMissingAudience
 )
 
+// ClaimsFunc return's the custom private claims embedded in the issued token for the given user info,
+// The returned value must be JSON serializable e.g struct or map.
+type ClaimsFunc func(info auth.Info) (interface{}, error)
+
 // IssueAccessToken issue jwt access token for the provided user info.
 func IssueAccessToken(info auth.Info, s SecretsKeeper, opts ...auth.Option) (string, error) {
 	return newAccessToken(s, opts...).issue(info)
 }
 
 type accessToken struct {
+	keeper  SecretsKeeper
+	dur     time.Duration
+	aud     string
+	iss     string
+	scp     []string
+	cnf     *claims.Confirmation
+	deny    DenyList
+	fields  map[string]struct{}
+	claims  ClaimsFunc
+	cr      oauth2.ClaimsResolver
+	enc     *encryption
+	client  string
+	profile bool
+	policy  *SigningPolicy
+	clock   auth.Clock
+	leeway  time.Duration
+}
+
+type encryption struct {
 	keeper SecretsKeeper
-	dur    time.Duration
-	aud    string
-	iss    string
-	scp    []string
+	encs   []string
+	only   bool
 }
 
 func (at accessToken) issue(info auth.Info) (string, error) {
-	now := time.Now().UTC().Add(-claims.DefaultLeeway)
+	now := at.clock.Now().UTC().Add(-at.leeway)
 	exp := now.Add(at.dur)
 
-	c := claims.Standard{
-		Subject:   info.GetID(),
-		Issuer:    at.iss,
-		Audience:  claims.StringOrList{at.aud},
-		ExpiresAt: (*claims.Time)(&exp),
-		IssuedAt:  (*claims.Time)(&now),
-		NotBefore: (*claims.Time)(&now),
-		Scope:     at.scp,
+	jti, err := randomString(16)
+	if err != nil {
+		return "", fmt.Errorf("strategies/jwt: %w", err)
+	}
+
+	c := jwt.AccessTokenClaims{
+		ClientID: at.client,
+		Standard: claims.Standard{
+			Subject:   info.GetID(),
+			Issuer:    at.iss,
+			Audience:  claims.StringOrList{at.aud},
+			ExpiresAt: (*claims.Time)(&exp),
+			IssuedAt:  (*claims.Time)(&now),
+			NotBefore: (*claims.Time)(&now),
+			Scope:     at.scp,
+			JWTID:     jti,
+
+			Confirmation: at.cnf,
+		},
+	}
+
+	if at.profile {
+		// zero time skips time based verification.
+		opts := claims.VerifyOptions{
+			Audience: claims.StringOrList{at.aud},
+			Time:     func() time.Time { return time.Time{} },
+		}
+
+		if err := c.Verify(opts); err != nil {
+			return "", fmt.Errorf("strategies/jwt: %w", err)
+		}
 	}
 
-	str, err := jwt.IssueToken(at.keeper, c, info)
+	dest, err := at.private(info)
+	if err != nil {
+		return "", fmt.Errorf("strategies/jwt: %w", err)
+	}
+
+	// registered claims must come last to take precedence over private claims.
+	dest = append(dest, c)
+
+	str, err := at.sign(dest...)
 	if err != nil {
 		return "", fmt.Errorf("strategies/jwt: %w", err)
 	}
@@ -89,24 +212,150 @@ func (at accessToken) parse(tstr string) (claims.Standard, auth.Info, error) {
 	}
 
 	info := auth.NewUserInfo("", "", nil, make(auth.Extensions))
-	c := claims.Standard{}
+	dest := interface{}(info)
+	c := jwt.AccessTokenClaims{}
 	opts := claims.VerifyOptions{
 		Audience: claims.StringOrList{at.aud},
 		Issuer:   at.iss,
-		Time: func() (t time.Time) {
-			return time.Now().UTC().Add(-claims.DefaultLeeway)
-		},
+		Clock:    at.clock,
+		Leeway:   &at.leeway,
 	}
 
-	if err := jwt.ParseToken(at.keeper, tstr, &c, info); err != nil {
+	if at.cr != nil {
+		dest = at.cr.New()
+	}
+
+	if err := at.verify(tstr, &c, dest); err != nil {
 		return fail(err)
 	}
 
-	if err := c.Verify(opts); err != nil {
+	verify := c.Standard.Verify
+	if at.profile {
+		verify = c.Verify
+	}
+
+	if err := verify(opts); err != nil {
 		return fail(err)
 	}
 
-	return c, info, nil
+	if at.cr != nil {
+		cr := dest.(oauth2.ClaimsResolver)
+		if err := cr.Verify(opts); err != nil {
+			return fail(err)
+		}
+		info = cr.Resolve()
+	}
+
+	if len(info.GetID()) == 0 {
+		info.SetID(c.Subject)
+	}
+
+	if at.deny != nil && len(c.JWTID) > 0 {
+		denied, err := at.deny.IsDenied(c.JWTID)
+		if err != nil {
+			return fail(err)
+		}
+
+		if denied {
+			return claims.Standard{}, nil, ErrRevokedToken
+		}
+	}
+
+	return c.Standard, info, nil
+}
+
+// sign issue signed, encrypted, or signed then encrypted token from the given dest.
+func (at accessToken) sign(dest ...interface{}) (string, error) {
+	typ := jwt.TokenType
+	if at.profile {
+		typ = jwt.AccessTokenType
+	}
+
+	if at.enc == nil {
+		return jwt.IssueTypedToken(at.keeper, typ, dest...)
+	}
+
+	if at.enc.only {
+		return jwt.IssueEncryptedToken(nil, at.enc.keeper, typ, at.enc.encs[0], dest...)
+	}
+
+	return jwt.IssueEncryptedToken(at.keeper, at.enc.keeper, typ, at.enc.encs[0], dest...)
+}
+
+// verify parse signed, encrypted, or signed then encrypted token to the given dest.
+func (at accessToken) verify(tstr string, dest ...interface{}) error {
+	typ := ""
+	if at.profile {
+		typ = jwt.AccessTokenType
+	}
+
+	if at.enc == nil {
+		return jwt.ParseTokenWithPolicy(at.keeper, at.policy, typ, tstr, dest...)
+	}
+
+	if at.enc.only {
+		return jwt.ParseEncryptedToken(nil, at.enc.keeper, nil, typ, at.enc.encs, tstr, dest...)
+	}
+
+	return jwt.ParseEncryptedToken(at.keeper, at.enc.keeper, at.policy, typ, at.enc.encs, tstr, dest...)
+}
+
+// private return's the user info fields and custom claims to be embedded in the token.
+func (at accessToken) private(info auth.Info) ([]interface{}, error) {
+	dest := []interface{}{}
+
+	if at.fields == nil {
+		dest = append(dest, info)
+	} else if len(at.fields) > 0 {
+		b, err := json.Marshal(info)
+		if err != nil {
+			return nil, err
+		}
+
+		m := make(map[string]interface{})
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+
+		for k := range m {
+			if _, ok := at.fields[k]; !ok {
+				delete(m, k)
+			}
+		}
+
+		dest = append(dest, m)
+	}
+
+	if at.claims != nil {
+		v, err := at.claims(info)
+		if err != nil {
+			return nil, err
+		}
+		dest = append(dest, v)
+	}
+
+	return dest, nil
+}
+
+// revoke adds the token id to the deny-list until the token expires,
+// or indefinitely when the token has no exp claim.
+func (at accessToken) revoke(tstr string) error {
+	c := claims.Standard{}
+
+	if err := at.verify(tstr, &c); err != nil {
+		return fmt.Errorf("strategies/jwt: %w", err)
+	}
+
+	if len(c.JWTID) == 0 {
+		return ErrMissingJTI
+	}
+
+	exp := time.Time{}
+	if c.ExpiresAt != nil {
+		exp = time.Time(*c.ExpiresAt).Add(at.leeway)
+	}
+
+	return at.deny.Deny(c.JWTID, exp)
 }
 
 func newAccessToken(s SecretsKeeper, opts ...auth.Option) *accessToken {
@@ -115,8 +364,19 @@ func newAccessToken(s SecretsKeeper, opts ...auth.Option) *accessToken {
 	t.aud = ""
 	t.iss = ""
 	t.dur = time.Minute * 5
+	t.clock = auth.SystemClock
+	t.leeway = claims.DefaultLeeway
 	for _, opt := range opts {
 		opt.Apply(t)
 	}
+
+	if t.enc != nil && t.enc.keeper == nil {
+		t.enc = nil
+	}
+
+	if t.enc != nil && len(t.enc.encs) == 0 {
+		t.enc.encs = []string{A256GCM}
+	}
+
 	return t
 }
@@ -1,10 +1,16 @@
 package jwt
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,12 +35,11 @@ func TestToken(t *testing.T) {
 		return new(testUser)
 	})
 
-	tk := new(accessToken)
-	tk.keeper = StaticSecret{
+	tk := newAccessToken(StaticSecret{
 		ID:        "kid",
 		Secret:    []byte("test-secret"),
 		Algorithm: HS256,
-	}
+	})
 	tk.dur = time.Hour
 	tk.iss = "test-iss"
 	tk.aud = "test-aud"
@@ -47,6 +52,23 @@ func TestToken(t *testing.T) {
 	assert.Equal(t, u, info)
 }
 
+func TestTokenConfirmation(t *testing.T) {
+	info := auth.NewDefaultUser("test", "test", nil, nil)
+	cnf := claims.Confirmation{JWKThumbprint: "test-jkt"}
+	s := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+
+	str, err := IssueAccessToken(info, s, SetConfirmation(cnf))
+	assert.NoError(t, err)
+
+	got, _, err := GetAuthenticateFunc(s)(context.TODO(), nil, str)
+	assert.NoError(t, err)
+	assert.Equal(t, cnf, token.GetConfirmation(got))
+}
+
 func TestTokenAlg(t *testing.T) {
 	info := auth.NewDefaultUser("test", "test", nil, nil)
 
@@ -79,6 +101,132 @@ func TestTokenKID(t *testing.T) {
 	assert.Contains(t, err.Error(), ErrMissingKID.Error())
 }
 
+func TestTokenPrivateClaims(t *testing.T) {
+	info := auth.NewDefaultUser("test", "test-id", []string{"admin"}, auth.Extensions{"large": []string{"ext"}})
+	s := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+
+	custom := func(info auth.Info) (interface{}, error) {
+		return map[string]interface{}{"tenant": "acme", "sub": "override"}, nil
+	}
+
+	str, err := IssueAccessToken(info, s, SetInfoFields("Name", "Groups"), SetCustomClaims(custom))
+	assert.NoError(t, err)
+
+	// default resolver
+	got, _, err := GetAuthenticateFunc(s)(context.TODO(), nil, str)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", got.GetUserName())
+	assert.Equal(t, "test-id", got.GetID())
+	assert.Equal(t, []string{"admin"}, got.GetGroups())
+	assert.Empty(t, got.GetExtensions().Values("large"))
+
+	// custom resolver
+	got, _, err = GetAuthenticateFunc(s, SetClaimResolver(new(tenantClaims)))(context.TODO(), nil, str)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", got.(*tenantClaims).Tenant)
+	assert.Equal(t, "test-id", got.GetID())
+
+	// custom resolver verify
+	str, err = IssueAccessToken(info, s)
+	assert.NoError(t, err)
+	_, _, err = GetAuthenticateFunc(s, SetClaimResolver(new(tenantClaims)))(context.TODO(), nil, str)
+	assert.Error(t, err)
+
+	// custom claims error
+	fail := func(auth.Info) (interface{}, error) { return nil, errors.New("test") }
+	_, err = IssueAccessToken(info, s, SetCustomClaims(fail))
+	assert.Error(t, err)
+}
+
+func TestTokenAccessTokenProfile(t *testing.T) {
+	info := auth.NewDefaultUser("test", "test-id", nil, nil)
+	s := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+
+	profile := []auth.Option{SetAccessTokenProfile(), SetIssuer("iss"), SetAudience("rs"), SetClientID("client")}
+
+	// it issue token with at+jwt typ and required claims.
+	str, err := IssueAccessToken(info, s, profile...)
+	assert.NoError(t, err)
+
+	got, _, err := GetAuthenticateFunc(s, profile...)(context.TODO(), nil, str)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-id", got.GetID())
+
+	// it return error when client_id missing.
+	_, err = IssueAccessToken(info, s, SetAccessTokenProfile(), SetIssuer("iss"), SetAudience("rs"))
+	assert.True(t, errors.Is(err, ErrMissingClaim))
+
+	// it return error when audience missing.
+	_, err = IssueAccessToken(info, s, SetAccessTokenProfile(), SetIssuer("iss"), SetClientID("client"))
+	assert.True(t, errors.Is(err, ErrMissingAudience))
+
+	// it return error when token typ is not at+jwt.
+	str, err = IssueAccessToken(info, s, SetIssuer("iss"), SetAudience("rs"), SetClientID("client"))
+	assert.NoError(t, err)
+	_, _, err = GetAuthenticateFunc(s, profile...)(context.TODO(), nil, str)
+	assert.True(t, errors.Is(err, ErrInvalidType))
+}
+
+func TestTokenClock(t *testing.T) {
+	info := auth.NewDefaultUser("test", "test-id", nil, nil)
+	s := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+
+	clock := authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	opts := []auth.Option{SetClock(clock), SetLeeway(time.Second), SetExpDuration(time.Minute)}
+
+	str, err := IssueAccessToken(info, s, opts...)
+	assert.NoError(t, err)
+
+	// it authenticate token issued in the past relative to the system clock.
+	start := clock.Now()
+	clock.Advance(time.Second)
+	_, exp, err := GetAuthenticateFunc(s, opts...)(context.TODO(), nil, str)
+	assert.NoError(t, err)
+	assert.Equal(t, start.Add(time.Minute-time.Second).Unix(), exp.Unix())
+
+	// it return error once the clock passes token exp.
+	clock.Advance(time.Minute)
+	_, _, err = GetAuthenticateFunc(s, opts...)(context.TODO(), nil, str)
+	assert.Error(t, err)
+}
+
+func TestZeroLeeway(t *testing.T) {
+	info := auth.NewUserInfo("test", "1", nil, nil)
+	s := StaticSecret{
+		ID:        "kid",
+		Secret:    []byte("test-secret"),
+		Algorithm: HS256,
+	}
+
+	clock := authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	opts := []auth.Option{SetClock(clock), SetLeeway(0), SetExpDuration(time.Minute)}
+
+	str, err := IssueAccessToken(info, s, opts...)
+	assert.NoError(t, err)
+
+	// zero leeway does not fallback to the default leeway.
+	clock.Advance(time.Minute)
+	_, _, err = GetAuthenticateFunc(s, opts...)(context.TODO(), nil, str)
+	assert.Error(t, err)
+
+	// it authenticate token expired within the default leeway.
+	clock.Advance(time.Second * 30)
+	_, _, err = GetAuthenticateFunc(s, SetClock(clock))(context.TODO(), nil, str)
+	assert.NoError(t, err)
+}
+
 func TestNewToken(t *testing.T) {
 	tk := newAccessToken(nil)
 	if assert.NotNil(t, tk) {
@@ -88,6 +236,26 @@ func TestNewToken(t *testing.T) {
 	}
 }
 
+type tenantClaims struct {
+	*auth.DefaultUser
+	Tenant string `json:"tenant"`
+}
+
+func (c *tenantClaims) New() oauth2.ClaimsResolver {
+	return &tenantClaims{DefaultUser: new(auth.DefaultUser)}
+}
+
+func (c *tenantClaims) Verify(claims.VerifyOptions) error {
+	if len(c.Tenant) == 0 {
+		return errors.New("missing tenant")
+	}
+	return nil
+}
+
+func (c *tenantClaims) Resolve() auth.Info {
+	return c
+}
+
 // testUser has been added to verify we still can marshal/unmarshal
 // customized auth.info from jwt claims.
 type testUser struct {
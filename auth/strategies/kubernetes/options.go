@@ -7,6 +7,7 @@ import (
 
 	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/internal"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
 )
 
 // SetServiceAccountToken sets kubernetes service account token
@@ -15,6 +16,13 @@ func SetServiceAccountToken(token string) auth.Option {
 	return internal.SetRequesterBearerToken(token)
 }
 
+// SetServiceAccountTokenSource sets kubernetes service account token source
+// for token review API, e.g clientcredentials.TokenSource.
+// The request retried once with a fresh token when rejected, if ts implements oauth2.TokenInvalidator.
+func SetServiceAccountTokenSource(ts oauth2.TokenSource) auth.Option {
+	return internal.SetRequesterTokenSource(ts)
+}
+
 // SetHTTPClient sets underlying http client.
 func SetHTTPClient(c *http.Client) auth.Option {
 	return internal.SetRequesterHTTPClient(c)
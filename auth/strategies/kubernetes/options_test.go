@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"context"
 	"crypto/tls"
 	"net/http"
 	"testing"
@@ -20,6 +21,18 @@ func TestSetServiceAccountToken(t *testing.T) {
 	assert.Equal(t, appj, r.Header.Get("Accept"))
 }
 
+type tokenSource string
+
+func (ts tokenSource) Token(context.Context) (string, error) {
+	return string(ts), nil
+}
+
+func TestSetServiceAccountTokenSource(t *testing.T) {
+	ts := tokenSource("test-token")
+	kr := newKubeReview(SetServiceAccountTokenSource(ts))
+	assert.Equal(t, ts, kr.requester.TokenSource)
+}
+
 func TestSetHTTPClient(t *testing.T) {
 	client := new(http.Client)
 	opt := SetHTTPClient(client)
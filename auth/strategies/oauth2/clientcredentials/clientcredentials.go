@@ -0,0 +1,201 @@
+// Package clientcredentials provides a token source that fetches access tokens,
+// using the oauth2 client credentials grant as defined in RFC 6749 section 4.4.
+// The token source typically used to authorize the calls to the authorization server endpoints,
+// e.g the token introspection or kubernetes token review endpoints.
+package clientcredentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/internal"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
+)
+
+// fetchTimeout bounds the shared token fetch, as it detached from the callers contexts.
+const fetchTimeout = time.Second * 30
+
+var (
+	// ErrMissingAccessToken is returned by TokenSource Token method,
+	// when the token endpoint response missing the access_token.
+	ErrMissingAccessToken = errors.New("strategies/oauth2/clientcredentials: Token response missing access_token")
+
+	// ErrUnsupportedTokenType is returned by TokenSource Token method,
+	// when the token endpoint response token_type is not bearer.
+	ErrUnsupportedTokenType = errors.New("strategies/oauth2/clientcredentials: Unsupported token type")
+)
+
+var (
+	_ oauth2.TokenSource      = (*TokenSource)(nil)
+	_ oauth2.TokenInvalidator = (*TokenSource)(nil)
+)
+
+// TokenSource implements oauth2.TokenSource and oauth2.TokenInvalidator,
+// and fetches access tokens from the token endpoint using the client credentials grant.
+//
+// The token cached until shortly before its expiry (default 10 seconds),
+// or until invalidated, and only one fetch in flight shared by concurrent callers.
+// A token response without expires_in cached until invalidated.
+type TokenSource struct {
+	mu        sync.Mutex
+	requester *internal.Requester
+	scopes    []string
+	params    url.Values
+	delta     time.Duration
+	clock     auth.Clock
+	token     string
+	expiresAt time.Time
+	call      *call
+}
+
+type call struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Token return's the cached access token if still valid,
+// Otherwise, it fetches a new access token from the token endpoint.
+func (ts *TokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+
+	if len(ts.token) > 0 && (ts.expiresAt.IsZero() || ts.clock.Now().Before(ts.expiresAt)) {
+		token := ts.token
+		ts.mu.Unlock()
+		return token, nil
+	}
+
+	c := ts.call
+	if c == nil {
+		c = &call{done: make(chan struct{})}
+		ts.call = c
+		go ts.refresh(c)
+	}
+
+	ts.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.token, c.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// refresh fetches a new access token, detached from the callers contexts,
+// as the fetch shared by all callers.
+func (ts *TokenSource) refresh(c *call) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	token, exp, err := ts.fetch(ctx)
+
+	ts.mu.Lock()
+	ts.call = nil
+	if err == nil {
+		ts.token = token
+		ts.expiresAt = exp
+	}
+	ts.mu.Unlock()
+
+	c.token, c.err = token, err
+	close(c.done)
+}
+
+// Invalidate discards the given token if it still the cached one,
+// so the next Token call fetches a new access token.
+func (ts *TokenSource) Invalidate(token string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token == token {
+		ts.token = ""
+		ts.expiresAt = time.Time{}
+	}
+}
+
+func (ts *TokenSource) fetch(ctx context.Context) (string, time.Time, error) {
+	fail := func(err error) (string, time.Time, error) {
+		return "", time.Time{}, fmt.Errorf("strategies/oauth2/clientcredentials: %w", err)
+	}
+
+	data := url.Values{}
+	for k, v := range ts.params {
+		data[k] = v
+	}
+
+	data.Set("grant_type", "client_credentials")
+
+	if len(ts.scopes) > 0 {
+		data.Set("scope", strings.Join(ts.scopes, " "))
+	}
+
+	now := ts.clock.Now()
+	resp := new(tokenResponse)
+	autherr := new(oauth2.ResponseError)
+
+	//nolint:bodyclose
+	r, err := ts.requester.Do(ctx, data, resp, autherr)
+
+	switch {
+	case err != nil:
+		return fail(err)
+	case r.StatusCode != http.StatusOK:
+		return fail(autherr)
+	case len(resp.AccessToken) == 0:
+		return "", time.Time{}, ErrMissingAccessToken
+	case len(resp.TokenType) > 0 && !strings.EqualFold(resp.TokenType, "bearer"):
+		return "", time.Time{}, ErrUnsupportedTokenType
+	}
+
+	exp := time.Time{}
+	if resp.ExpiresIn > 0 {
+		lifetime, delta := time.Duration(resp.ExpiresIn)*time.Second, ts.delta
+		// a delta that outlives the token would make it expired on arrival.
+		if delta >= lifetime {
+			delta = lifetime / 2
+		}
+		exp = now.Add(lifetime - delta)
+	}
+
+	return resp.AccessToken, exp, nil
+}
+
+// New return's new client credentials token source,
+// that fetches access tokens from the token endpoint at the given addr.
+// The client typically authenticated using SetBasicAuth, SetPrivateKeyJWT, or SetClientSecretJWT.
+func New(addr string, opts ...auth.Option) *TokenSource {
+	r := internal.NewRequester(addr)
+	r.KeepUnmarshalling = true
+	r.Marshal = func(v interface{}) ([]byte, error) {
+		u := v.(url.Values)
+		return []byte(u.Encode()), nil
+	}
+	r.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	r.SetHeader("Accept", "application/json")
+
+	ts := new(TokenSource)
+	ts.requester = r
+	ts.delta = time.Second * 10
+	ts.clock = auth.SystemClock
+
+	for _, opt := range opts {
+		opt.Apply(ts.requester)
+		opt.Apply(ts)
+	}
+
+	return ts
+}
@@ -0,0 +1,245 @@
+package clientcredentials
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+)
+
+func TestTokenSource(t *testing.T) {
+	table := []struct {
+		name  string
+		code  int
+		body  string
+		token string
+		err   string
+	}{
+		{
+			name:  "it return access token",
+			code:  200,
+			body:  `{"access_token":"token","token_type":"Bearer","expires_in":3600}`,
+			token: "token",
+		},
+		{
+			name:  "it return access token when token type missing",
+			code:  200,
+			body:  `{"access_token":"token"}`,
+			token: "token",
+		},
+		{
+			name: "it return error when server return error status",
+			code: 401,
+			body: `{"error":"invalid_client","error_description":"Client authentication failed"}`,
+			err:  "strategies/oauth2/clientcredentials: strategies/oauth2: invalid_client, Client authentication failed",
+		},
+		{
+			name: "it return error when access token missing",
+			code: 200,
+			body: `{"token_type":"Bearer"}`,
+			err:  ErrMissingAccessToken.Error(),
+		},
+		{
+			name: "it return error when token type unsupported",
+			code: 200,
+			body: `{"access_token":"token","token_type":"DPoP"}`,
+			err:  ErrUnsupportedTokenType.Error(),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				id, secret, _ := r.BasicAuth()
+				assert.Equal(t, "client", id)
+				assert.Equal(t, "secret", secret)
+				assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+				assert.Equal(t, "read write", r.PostForm.Get("scope"))
+				assert.Equal(t, "https://rs.example.com", r.PostForm.Get("resource"))
+				w.WriteHeader(tt.code)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			ts := New(
+				srv.URL,
+				SetBasicAuth("client", "secret"),
+				SetScopes("read", "write"),
+				SetEndpointParams(url.Values{"resource": []string{"https://rs.example.com"}}),
+			)
+
+			token, err := ts.Token(context.TODO())
+
+			if len(tt.err) > 0 {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.token, token)
+		})
+	}
+}
+
+func TestTokenSourceCache(t *testing.T) {
+	var counter int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&counter, 1)
+		_, _ = w.Write([]byte(`{"access_token":"token","expires_in":60}`))
+	}))
+	defer srv.Close()
+
+	clock := authtest.NewFakeClock(time.Now())
+	ts := New(srv.URL, SetClock(clock))
+
+	for i := 0; i < 3; i++ {
+		_, err := ts.Token(context.TODO())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+
+	// the default expiry delta is 10 seconds.
+	clock.Advance(time.Second * 49)
+	_, _ = ts.Token(context.TODO())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+
+	clock.Advance(time.Second * 2)
+	_, _ = ts.Token(context.TODO())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&counter))
+
+	ts.Invalidate("other")
+	_, _ = ts.Token(context.TODO())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&counter))
+
+	ts.Invalidate("token")
+	_, _ = ts.Token(context.TODO())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&counter))
+}
+
+func TestTokenSourceConcurrent(t *testing.T) {
+	var counter int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&counter, 1)
+		time.Sleep(time.Millisecond * 50)
+		_, _ = w.Write([]byte(`{"access_token":"token","expires_in":60}`))
+	}))
+	defer srv.Close()
+
+	ts := New(srv.URL)
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := ts.Token(context.TODO())
+			assert.NoError(t, err)
+			assert.Equal(t, "token", token)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+}
+
+func TestTokenSourceCanceledCaller(t *testing.T) {
+	var counter int32
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&counter, 1)
+		<-release
+		_, _ = w.Write([]byte(`{"access_token":"token","expires_in":60}`))
+	}))
+	defer srv.Close()
+
+	ts := New(srv.URL)
+
+	// the first caller lead the fetch, and give up before it completes.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ts.Token(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		token, err := ts.Token(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token)
+	}()
+
+	close(release)
+	<-done
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+}
+
+func TestTokenSourceExpiryDelta(t *testing.T) {
+	table := []struct {
+		name      string
+		expiresIn int
+		delta     time.Duration
+		valid     time.Duration
+	}{
+		{
+			name:      "it subtract delta from token lifetime",
+			expiresIn: 60,
+			delta:     time.Second * 10,
+			valid:     time.Second * 50,
+		},
+		{
+			name:      "it reduce delta to half of the token lifetime when delta equal to lifetime",
+			expiresIn: 10,
+			delta:     time.Second * 10,
+			valid:     time.Second * 5,
+		},
+		{
+			name:      "it reduce delta to half of the token lifetime when delta exceeds lifetime",
+			expiresIn: 5,
+			delta:     time.Second * 10,
+			valid:     time.Millisecond * 2500,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			var counter int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&counter, 1)
+				_, _ = fmt.Fprintf(w, `{"access_token":"token","expires_in":%d}`, tt.expiresIn)
+			}))
+			defer srv.Close()
+
+			clock := authtest.NewFakeClock(time.Now())
+			ts := New(srv.URL, SetClock(clock), SetExpiryDelta(tt.delta))
+
+			_, err := ts.Token(context.TODO())
+			require.NoError(t, err)
+
+			clock.Advance(tt.valid - time.Millisecond)
+			_, _ = ts.Token(context.TODO())
+			assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+
+			clock.Advance(time.Millisecond)
+			_, _ = ts.Token(context.TODO())
+			assert.Equal(t, int32(2), atomic.LoadInt32(&counter))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package clientcredentials
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/internal"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+)
+
+// SetBasicAuth sets the token request's Authorization header to use
+// HTTP Basic Authentication with the provided clientid and clientsecret.
+func SetBasicAuth(clientid, clientsecret string) auth.Option {
+	return internal.SetRequesterBasicAuth(clientid, clientsecret)
+}
+
+// SetPrivateKeyJWT sets the token request's to authenticate the client
+// using private_key_jwt method as defined in RFC 7523,
+// a fresh client assertion JWT signed with the client private key held by k for each request.
+func SetPrivateKeyJWT(clientid string, k jwt.SecretsKeeper) auth.Option {
+	return internal.SetRequesterClientAssertion(clientid, k, false)
+}
+
+// SetClientSecretJWT sets the token request's to authenticate the client
+// using client_secret_jwt method as defined in RFC 7523,
+// a fresh client assertion JWT signed with the client secret held by k (HMAC) for each request.
+func SetClientSecretJWT(clientid string, k jwt.SecretsKeeper) auth.Option {
+	return internal.SetRequesterClientAssertion(clientid, k, true)
+}
+
+// SetHTTPClient sets underlying http client.
+func SetHTTPClient(c *http.Client) auth.Option {
+	return internal.SetRequesterHTTPClient(c)
+}
+
+// SetTLSConfig sets underlying http client tls.
+func SetTLSConfig(tls *tls.Config) auth.Option {
+	return internal.SetRequesterTLSConfig(tls)
+}
+
+// SetClientTransport sets underlying http client transport.
+func SetClientTransport(rt http.RoundTripper) auth.Option {
+	return internal.SetRequesterClientTransport(rt)
+}
+
+// SetScopes sets the requested access token scopes.
+func SetScopes(scopes ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if ts, ok := v.(*TokenSource); ok {
+			ts.scopes = scopes
+		}
+	})
+}
+
+// SetEndpointParams sets additional token request parameters,
+// e.g the resource indicator as defined in RFC 8707.
+func SetEndpointParams(params url.Values) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if ts, ok := v.(*TokenSource); ok {
+			ts.params = params
+		}
+	})
+}
+
+// SetExpiryDelta sets how long before the access token expiry a new one fetched,
+// a delta not shorter than the token lifetime reduced to half of the lifetime.
+// Default: 10 seconds.
+func SetExpiryDelta(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if ts, ok := v.(*TokenSource); ok {
+			ts.delta = d
+		}
+	})
+}
+
+// SetClock sets the clock used to compute the access token expiry,
+// and the client assertion iat and exp claims.
+// Default: auth.SystemClock.
+func SetClock(c auth.Clock) auth.Option {
+	ca := internal.SetRequesterClientAssertionClock(c)
+	return auth.OptionFunc(func(v interface{}) {
+		switch t := v.(type) {
+		case *TokenSource:
+			t.clock = c
+		case *internal.Requester:
+			ca.Apply(t)
+		}
+	})
+}
@@ -0,0 +1,295 @@
+// Package discovery provides authorization server metadata discovery,
+// as defined in OpenID Connect Discovery 1.0 and RFC 8414,
+// to construct the oauth2 jwt, userinfo, and introspection strategies from the issuer metadata.
+//
+//	p := discovery.NewProvider("https://issuer.example.com")
+//	if _, err := p.Metadata(ctx); err != nil {
+//		...
+//	}
+//	strategy := p.NewJWT(cache)
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/internal"
+	"github.com/shaj13/go-guardian/v2/auth/internal/header"
+)
+
+const (
+	openidConfiguration = "/.well-known/openid-configuration"
+	oauthServer         = "/.well-known/oauth-authorization-server"
+	cacheControl        = "cache-control"
+	retryInterval       = time.Minute
+	failureInterval     = time.Second * 10
+	fetchTimeout        = time.Second * 30
+)
+
+var (
+	// ErrIssuerMismatch is returned by Provider Metadata method,
+	// when the metadata issuer does not match the provider issuer.
+	ErrIssuerMismatch = errors.New("strategies/oauth2/discovery: Metadata issuer does not match the provider issuer")
+
+	// ErrMissingEndpoint is returned by the discovered strategies,
+	// when the metadata missing the strategy endpoint.
+	ErrMissingEndpoint = errors.New("strategies/oauth2/discovery: Metadata missing endpoint")
+)
+
+// Metadata represents the authorization server metadata,
+// as defined in OpenID Connect Discovery 1.0 and RFC 8414.
+type Metadata struct {
+	Issuer                                     string   `json:"issuer"`
+	AuthorizationEndpoint                      string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                              string   `json:"token_endpoint,omitempty"`
+	UserInfoEndpoint                           string   `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                                    string   `json:"jwks_uri,omitempty"`
+	RegistrationEndpoint                       string   `json:"registration_endpoint,omitempty"`
+	IntrospectionEndpoint                      string   `json:"introspection_endpoint,omitempty"`
+	RevocationEndpoint                         string   `json:"revocation_endpoint,omitempty"`
+	EndSessionEndpoint                         string   `json:"end_session_endpoint,omitempty"`
+	ScopesSupported                            []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported                     []string `json:"response_types_supported,omitempty"`
+	GrantTypesSupported                        []string `json:"grant_types_supported,omitempty"`
+	SubjectTypesSupported                      []string `json:"subject_types_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported           []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	TokenEndpointAuthMethodsSupported          []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	IntrospectionEndpointAuthMethodsSupported  []string `json:"introspection_endpoint_auth_methods_supported,omitempty"` //nolint:lll
+	ClaimsSupported                            []string `json:"claims_supported,omitempty"`
+	CodeChallengeMethodsSupported              []string `json:"code_challenge_methods_supported,omitempty"`
+	DPoPSigningAlgValuesSupported              []string `json:"dpop_signing_alg_values_supported,omitempty"`
+	TLSClientCertificateBoundAccessTokens      bool     `json:"tls_client_certificate_bound_access_tokens,omitempty"`
+	AuthorizationResponseIssParameterSupported bool     `json:"authorization_response_iss_parameter_supported,omitempty"` //nolint:lll
+}
+
+// Provider discovers and caches the issuer metadata.
+//
+// The metadata cached based on the cache-control header if exist,
+// Otherwise, fallback to an interval duration.
+// Once expired, the stale metadata served while refreshed in the background,
+// Once the refresh fails, the stale metadata used and the refresh retried after a minute,
+// Or when no metadata cached, the failure returned for 10 seconds.
+//
+// Only one fetch in flight shared by concurrent callers.
+type Provider struct {
+	mu         sync.Mutex
+	issuer     string
+	requesters []*internal.Requester
+	metadata   *Metadata
+	err        error
+	expiresAt  time.Time
+	interval   time.Duration
+	clock      auth.Clock
+	call       *call
+}
+
+type call struct {
+	done     chan struct{}
+	metadata *Metadata
+	err      error
+}
+
+// clone return's a deep copy of m, to not expose the cached metadata to callers mutation.
+func (m *Metadata) clone() *Metadata {
+	if m == nil {
+		return nil
+	}
+
+	c := *m
+	for _, v := range []*[]string{
+		&c.ScopesSupported,
+		&c.ResponseTypesSupported,
+		&c.GrantTypesSupported,
+		&c.SubjectTypesSupported,
+		&c.IDTokenSigningAlgValuesSupported,
+		&c.TokenEndpointAuthMethodsSupported,
+		&c.IntrospectionEndpointAuthMethodsSupported,
+		&c.ClaimsSupported,
+		&c.CodeChallengeMethodsSupported,
+		&c.DPoPSigningAlgValuesSupported,
+	} {
+		if *v != nil {
+			*v = append([]string(nil), *v...)
+		}
+	}
+
+	return &c
+}
+
+// Issuer return's the provider issuer.
+func (p *Provider) Issuer() string {
+	return p.issuer
+}
+
+// Metadata return's a copy of the issuer metadata,
+// fetched from the OpenID Connect discovery endpoint,
+// or the RFC 8414 well-known endpoint when the former fails.
+//
+// Once the cached metadata expires, the stale metadata returned,
+// while a single refresh runs in the background.
+//
+// Typically called once at startup to fail fast,
+// when the issuer metadata unavailable or invalid.
+func (p *Provider) Metadata(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+
+	if p.clock.Now().Before(p.expiresAt) {
+		m, err := p.metadata, p.err
+		p.mu.Unlock()
+		return m.clone(), err
+	}
+
+	c := p.call
+	if c == nil {
+		c = &call{done: make(chan struct{})}
+		p.call = c
+		go p.refresh(c)
+	}
+
+	// serve the stale metadata, to not stall the callers on the refresh.
+	if m := p.metadata; m != nil {
+		p.mu.Unlock()
+		return m.clone(), nil
+	}
+
+	p.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.metadata.clone(), c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh fetches the metadata, detached from the callers contexts,
+// as the fetch shared by all callers.
+func (p *Provider) refresh(c *call) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	m, h, err := p.fetch(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case err == nil:
+		p.metadata, p.err = m, nil
+		p.setExpiresAt(h)
+	case p.metadata != nil:
+		p.expiresAt = p.clock.Now().Add(retryInterval)
+	default:
+		p.err = err
+		p.expiresAt = p.clock.Now().Add(failureInterval)
+	}
+
+	p.call = nil
+	c.metadata, c.err = p.metadata, p.err
+	close(c.done)
+}
+
+func (p *Provider) fetch(ctx context.Context) (*Metadata, http.Header, error) {
+	var err error
+
+	for _, r := range p.requesters {
+		m, h, ferr := p.fetchFrom(ctx, r)
+		if ferr == nil {
+			return m, h, nil
+		}
+
+		if err == nil || !errors.Is(err, ErrIssuerMismatch) {
+			err = ferr
+		}
+	}
+
+	return nil, nil, err
+}
+
+func (p *Provider) fetchFrom(ctx context.Context, r *internal.Requester) (*Metadata, http.Header, error) {
+	fail := func(err error) (*Metadata, http.Header, error) {
+		return nil, nil, fmt.Errorf("strategies/oauth2/discovery: %w", err)
+	}
+
+	m := new(Metadata)
+
+	//nolint:bodyclose
+	resp, err := r.Do(ctx, nil, nil, m)
+	if err != nil {
+		return fail(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fail(fmt.Errorf("Metadata endpoint %s returned %v status code", r.Addr, resp.StatusCode))
+	}
+
+	if m.Issuer != p.issuer {
+		return nil, nil, ErrIssuerMismatch
+	}
+
+	return m, resp.Header, nil
+}
+
+func (p *Provider) setExpiresAt(h http.Header) {
+	interval := p.interval
+
+	if v, ok := header.ParsePairs(h, cacheControl)["max-age"]; ok {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err == nil {
+			interval = time.Duration(i) * time.Second
+		}
+	}
+
+	p.expiresAt = p.clock.Now().Add(interval)
+}
+
+// wellKnown return's the issuer metadata addresses,
+// the OpenID Connect discovery address appends the well-known path to the issuer,
+// while RFC 8414 inserts it between the issuer host and path.
+func wellKnown(issuer string) []string {
+	addrs := []string{strings.TrimSuffix(issuer, "/") + openidConfiguration}
+
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return addrs
+	}
+
+	u.Path = oauthServer + strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+
+	return append(addrs, u.String())
+}
+
+// NewProvider return's new provider to discover the given issuer metadata,
+// the metadata fetched on the first use.
+func NewProvider(issuer string, opts ...auth.Option) *Provider {
+	p := &Provider{
+		issuer:   issuer,
+		interval: time.Hour,
+		clock:    auth.SystemClock,
+	}
+
+	for _, addr := range wellKnown(issuer) {
+		r := internal.NewRequester(addr)
+		r.Method = http.MethodGet
+		r.SetHeader("Accept", "application/json")
+		p.requesters = append(p.requesters, r)
+	}
+
+	for _, opt := range opts {
+		opt.Apply(p)
+		for _, r := range p.requesters {
+			opt.Apply(r)
+		}
+	}
+
+	return p
+}
@@ -0,0 +1,453 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/go-jose/go-jose.v2"
+
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+	oauth2jwt "github.com/shaj13/go-guardian/v2/auth/strategies/oauth2/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
+)
+
+func TestMetadata(t *testing.T) {
+	table := []struct {
+		name      string
+		path      string
+		wellKnown string
+		issuer    func(srv string) string
+		err       error
+		contains  string
+	}{
+		{
+			name:      "it fetch the openid configuration",
+			wellKnown: "/.well-known/openid-configuration",
+		},
+		{
+			name:      "it fetch the openid configuration of issuer with path",
+			path:      "/tenant",
+			wellKnown: "/tenant/.well-known/openid-configuration",
+		},
+		{
+			name:      "it fallback to the RFC 8414 authorization server metadata",
+			path:      "/tenant",
+			wellKnown: "/.well-known/oauth-authorization-server/tenant",
+		},
+		{
+			name:      "it return error when metadata issuer does not match",
+			wellKnown: "/.well-known/openid-configuration",
+			issuer:    func(string) string { return "https://other.example.com" },
+			err:       ErrIssuerMismatch,
+		},
+		{
+			name:      "it return error when metadata not found",
+			wellKnown: "/unknown",
+			contains:  "Failed to unmarshal",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := mockAuthzServer(t, nil)
+			defer srv.Close()
+
+			issuer := srv.URL + tt.path
+			m := srv.metadata(issuer)
+			if tt.issuer != nil {
+				m.Issuer = tt.issuer(srv.URL)
+			}
+			srv.serve(tt.wellKnown, m, nil)
+
+			got, err := NewProvider(issuer).Metadata(context.Background())
+
+			if tt.err != nil || len(tt.contains) > 0 {
+				require.Error(t, err)
+				assert.True(t, tt.err == nil || errors.Is(err, tt.err), "got %v", err)
+				assert.Contains(t, err.Error(), tt.contains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, m, got)
+		})
+	}
+}
+
+func TestMetadataRefresh(t *testing.T) {
+	ctx := context.Background()
+	clock := authtest.NewFakeClock(time.Now())
+	counter := 0
+	srv := mockAuthzServer(t, &counter)
+	defer srv.Close()
+
+	m := srv.metadata(srv.URL)
+	srv.serve("/.well-known/openid-configuration", m, http.Header{"Cache-Control": {"max-age=60"}})
+	p := NewProvider(srv.URL, SetClock(clock))
+
+	_, err := p.Metadata(ctx)
+	require.NoError(t, err)
+	_, err = p.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, counter, "it cache the metadata")
+
+	clock.Advance(time.Second * 61)
+	updated := *m
+	updated.UserInfoEndpoint = srv.URL + "/v2/userinfo"
+	srv.serve("/.well-known/openid-configuration", &updated, nil)
+
+	got, err := p.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, m, got, "it serve the stale metadata while refreshing")
+
+	waitRefresh(p)
+
+	got, err = p.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, counter, "it refresh the metadata once max-age elapsed")
+	assert.Equal(t, &updated, got)
+
+	clock.Advance(time.Hour)
+	srv.serve("/.well-known/openid-configuration", nil, nil)
+
+	_, _ = p.Metadata(ctx)
+	waitRefresh(p)
+
+	got, err = p.Metadata(ctx)
+	require.NoError(t, err, "it use the stale metadata when refresh fails")
+	assert.Equal(t, &updated, got)
+}
+
+func TestMetadataCopy(t *testing.T) {
+	srv := mockAuthzServer(t, nil)
+	defer srv.Close()
+
+	srv.serve("/.well-known/openid-configuration", srv.metadata(srv.URL), nil)
+	p := NewProvider(srv.URL)
+
+	got, err := p.Metadata(context.Background())
+	require.NoError(t, err)
+	got.JWKSURI = "https://attacker.example.com/jwks"
+	got.ResponseTypesSupported[0] = "token"
+
+	got, err = p.Metadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/jwks", got.JWKSURI)
+	assert.Equal(t, []string{"code"}, got.ResponseTypesSupported)
+}
+
+func TestMetadataConcurrent(t *testing.T) {
+	var counter int32
+
+	release := make(chan struct{})
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&counter, 1)
+		<-release
+		_ = json.NewEncoder(w).Encode(Metadata{Issuer: srv.URL})
+	}))
+	defer srv.Close()
+
+	p := NewProvider(srv.URL)
+
+	// it return the caller context error, while the fetch in flight.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	_, err := p.Metadata(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m, err := p.Metadata(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, srv.URL, m.Issuer)
+		}()
+	}
+
+	time.Sleep(time.Millisecond * 10)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&counter))
+}
+
+func TestMetadataFailure(t *testing.T) {
+	var counter int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&counter, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	clock := authtest.NewFakeClock(time.Now())
+	p := NewProvider(srv.URL, SetClock(clock))
+
+	_, err := p.Metadata(context.Background())
+	assert.Error(t, err)
+	_, err = p.Metadata(context.Background())
+	assert.Error(t, err)
+
+	// both well-known endpoints requested once.
+	assert.Equal(t, int32(2), atomic.LoadInt32(&counter), "it cache the failure")
+
+	clock.Advance(time.Second * 10)
+	_, err = p.Metadata(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&counter), "it retry once the failure expired")
+}
+
+func TestStrategies(t *testing.T) {
+	srv := mockAuthzServer(t, nil)
+	defer srv.Close()
+	srv.serve("/.well-known/openid-configuration", srv.metadata(srv.URL), nil)
+	p := NewProvider(srv.URL)
+
+	table := []struct {
+		name     string
+		token    string
+		fn       token.AuthenticateFunc
+		contains string
+	}{
+		{
+			name:  "it authenticate jwt issued by the provider issuer",
+			token: issueJWT(t, srv.URL),
+			fn:    p.GetJWTAuthenticateFunc(),
+		},
+		{
+			name:     "it reject jwt issued by other issuer",
+			token:    issueJWT(t, "https://other.example.com"),
+			fn:       p.GetJWTAuthenticateFunc(),
+			contains: "issuer name does not match",
+		},
+		{
+			name:  "it authenticate using the userinfo endpoint",
+			token: "token",
+			fn:    p.GetUserInfoAuthenticateFunc(),
+		},
+		{
+			name:  "it authenticate using the introspection endpoint",
+			token: "token",
+			fn:    p.GetIntrospectionAuthenticateFunc(),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			info, _, err := tt.fn(context.Background(), nil, tt.token)
+
+			if len(tt.contains) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.contains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "test", info.GetID())
+		})
+	}
+}
+
+func TestStrategiesEndpoint(t *testing.T) {
+	srv := mockAuthzServer(t, nil)
+	defer srv.Close()
+
+	m := srv.metadata(srv.URL)
+	m.IntrospectionEndpoint = ""
+	srv.serve("/.well-known/openid-configuration", m, nil)
+	p := NewProvider(srv.URL)
+
+	// it return error when metadata missing the endpoint.
+	_, _, err := p.GetIntrospectionAuthenticateFunc()(context.Background(), nil, "token")
+	assert.True(t, errors.Is(err, ErrMissingEndpoint), "got %v", err)
+
+	// it rebuild the strategy once the endpoint changes.
+	clock := authtest.NewFakeClock(time.Now())
+	p = NewProvider(srv.URL, SetClock(clock))
+	fn := p.GetUserInfoAuthenticateFunc()
+
+	_, _, err = fn(context.Background(), nil, "token")
+	require.NoError(t, err)
+
+	m.UserInfoEndpoint = srv.URL + "/unknown"
+	srv.serve("/.well-known/openid-configuration", m, nil)
+	clock.Advance(time.Hour * 2)
+
+	_, _ = p.Metadata(context.Background())
+	waitRefresh(p)
+
+	_, _, err = fn(context.Background(), nil, "token")
+	assert.Error(t, err)
+}
+
+func TestNewIDTokenVerifier(t *testing.T) {
+	srv := mockAuthzServer(t, nil)
+	defer srv.Close()
+	srv.serve("/.well-known/openid-configuration", srv.metadata(srv.URL), nil)
+
+	v, err := NewProvider(srv.URL).NewIDTokenVerifier(context.Background(), "client")
+	require.NoError(t, err)
+
+	now := time.Now()
+	c := map[string]interface{}{
+		"sub": "test",
+		"iss": srv.URL,
+		"aud": "client",
+		"exp": now.Add(time.Hour).Unix(),
+		"iat": now.Add(-time.Minute * 5).Unix(),
+	}
+
+	str, err := jwt.IssueToken(newTestKeeper(t), c)
+	require.NoError(t, err)
+
+	it, err := v.Verify(context.Background(), str, oauth2jwt.IDTokenOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "test", it.GetID())
+}
+
+type authzServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	routes  map[string]func(w http.ResponseWriter)
+	counter *int
+}
+
+func (s *authzServer) metadata(issuer string) *Metadata {
+	return &Metadata{
+		Issuer:                 issuer,
+		JWKSURI:                s.URL + "/jwks",
+		UserInfoEndpoint:       s.URL + "/userinfo",
+		IntrospectionEndpoint:  s.URL + "/introspect",
+		ResponseTypesSupported: []string{"code"},
+	}
+}
+
+// serve serves the metadata at the given path, or not found if nil.
+func (s *authzServer) serve(path string, m *Metadata, h http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes = map[string]func(w http.ResponseWriter){
+		"/jwks":       s.routes["/jwks"],
+		"/userinfo":   s.routes["/userinfo"],
+		"/introspect": s.routes["/introspect"],
+	}
+
+	if m == nil {
+		return
+	}
+
+	s.routes[path] = func(w http.ResponseWriter) {
+		for k, v := range h {
+			w.Header()[k] = v
+		}
+		if s.counter != nil {
+			*s.counter++
+		}
+		_ = json.NewEncoder(w).Encode(m)
+	}
+}
+
+func (s *authzServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	route, ok := s.routes[r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	route(w)
+}
+
+// waitRefresh waits for the in flight metadata refresh if any.
+func waitRefresh(p *Provider) {
+	p.mu.Lock()
+	c := p.call
+	p.mu.Unlock()
+
+	if c != nil {
+		<-c.done
+	}
+}
+
+func mockAuthzServer(tb testing.TB, counter *int) *authzServer {
+	jwks, err := ioutil.ReadFile("./testdata/jwks.json")
+	if err != nil {
+		tb.Fatalf("Failed to read testdata file Err: %s", err)
+	}
+
+	write := func(body string) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			_, _ = w.Write([]byte(body))
+		}
+	}
+
+	s := &authzServer{counter: counter}
+	s.routes = map[string]func(w http.ResponseWriter){
+		"/jwks":       write(string(jwks)),
+		"/userinfo":   write(`{"sub":"test"}`),
+		"/introspect": write(`{"active":true,"sub":"test"}`),
+	}
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+func issueJWT(tb testing.TB, issuer string) string {
+	exp := claims.Time(time.Now().Add(time.Hour))
+	c := claims.Standard{
+		Subject:   "test",
+		Issuer:    issuer,
+		ExpiresAt: &exp,
+	}
+
+	str, err := jwt.IssueToken(newTestKeeper(tb), c)
+	if err != nil {
+		tb.Fatalf("Failed to generate test JWT token, Err: %v", err)
+	}
+
+	return str
+}
+
+type testKeeper struct {
+	key jose.JSONWebKey
+}
+
+func (k testKeeper) KID() string {
+	return k.key.KeyID
+}
+
+func (k testKeeper) Get(string) (interface{}, string, error) {
+	return k.key.Key, k.key.Algorithm, nil
+}
+
+func newTestKeeper(tb testing.TB) testKeeper {
+	buf, err := ioutil.ReadFile("./testdata/jwks.json")
+	if err != nil {
+		tb.Fatalf("Failed to read testdata file Err: %s", err)
+	}
+
+	set := jose.JSONWebKeySet{}
+	if err := json.Unmarshal(buf, &set); err != nil {
+		tb.Fatalf("Failed to unmarshal testdata file Err: %s", err)
+	}
+
+	return testKeeper{key: set.Keys[0]}
+}
@@ -0,0 +1,45 @@
+package discovery
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/internal"
+)
+
+// SetHTTPClient sets the underlying http client that used to get the issuer metadata.
+func SetHTTPClient(c *http.Client) auth.Option {
+	return internal.SetRequesterHTTPClient(c)
+}
+
+// SetTLSConfig sets tls config underlying http client tls that used to get the issuer metadata.
+func SetTLSConfig(tls *tls.Config) auth.Option {
+	return internal.SetRequesterTLSConfig(tls)
+}
+
+// SetClientTransport sets underlying http client transport that used to get the issuer metadata.
+func SetClientTransport(rt http.RoundTripper) auth.Option {
+	return internal.SetRequesterClientTransport(rt)
+}
+
+// SetInterval sets the fallback interval duration to refresh the issuer metadata.
+// Default: 1h.
+func SetInterval(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*Provider); ok {
+			p.interval = d
+		}
+	})
+}
+
+// SetClock sets the clock used to refresh the issuer metadata.
+// Default: auth.SystemClock.
+func SetClock(c auth.Clock) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*Provider); ok {
+			p.clock = c
+		}
+	})
+}
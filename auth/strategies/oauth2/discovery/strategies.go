@@ -0,0 +1,147 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2/introspection"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2/userinfo"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
+)
+
+// GetJWTAuthenticateFunc return function to authenticate request using oauth2 jwt access token or openid IDToken,
+// verified against the metadata jwks_uri keys, and the token issuer must match the provider issuer.
+//
+// The opts are the oauth2/jwt strategy options.
+// The returned function typically used with the token strategy.
+func (p *Provider) GetJWTAuthenticateFunc(opts ...auth.Option) token.AuthenticateFunc {
+	return p.authenticateFunc("jwks_uri", func(m *Metadata) string {
+		return m.JWKSURI
+	}, func(addr string) token.AuthenticateFunc {
+		return jwt.GetAuthenticateFunc(addr, append([]auth.Option{jwt.SetIssuer(p.issuer)}, opts...)...)
+	})
+}
+
+// NewJWT return strategy authenticate request using oauth2 jwt access token or openid IDToken,
+// verified against the metadata jwks_uri keys, and the token issuer must match the provider issuer.
+//
+// The opts are the oauth2/jwt strategy options.
+func (p *Provider) NewJWT(c auth.Cache, opts ...auth.Option) auth.Strategy {
+	return token.New(p.GetJWTAuthenticateFunc(opts...), c, opts...)
+}
+
+// NewIDTokenVerifier return's OpenID Connect ID token verifier for the given client id,
+// verifies ID tokens against the metadata jwks_uri keys, and the token issuer must match the provider issuer.
+//
+// The opts are the oauth2/jwt strategy options.
+func (p *Provider) NewIDTokenVerifier(ctx context.Context, clientID string, opts ...auth.Option) (*jwt.IDTokenVerifier, error) { //nolint:lll
+	m, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.JWKSURI) == 0 {
+		return nil, fmt.Errorf("%w jwks_uri", ErrMissingEndpoint)
+	}
+
+	opts = append([]auth.Option{jwt.SetIssuer(p.issuer)}, opts...)
+	return jwt.NewIDTokenVerifier(m.JWKSURI, clientID, opts...), nil
+}
+
+// GetUserInfoAuthenticateFunc return function to authenticate request using the metadata userinfo_endpoint.
+//
+// The opts are the oauth2/userinfo strategy options.
+// The returned function typically used with the token strategy.
+func (p *Provider) GetUserInfoAuthenticateFunc(opts ...auth.Option) token.AuthenticateFunc {
+	return p.authenticateFunc("userinfo_endpoint", func(m *Metadata) string {
+		return m.UserInfoEndpoint
+	}, func(addr string) token.AuthenticateFunc {
+		return userinfo.GetAuthenticateFunc(addr, opts...)
+	})
+}
+
+// NewUserInfo return strategy authenticate request using the metadata userinfo_endpoint.
+//
+// The opts are the oauth2/userinfo strategy options.
+func (p *Provider) NewUserInfo(c auth.Cache, opts ...auth.Option) auth.Strategy {
+	return token.New(p.GetUserInfoAuthenticateFunc(opts...), c, opts...)
+}
+
+// GetIntrospectionAuthenticateFunc return function to authenticate request using the metadata introspection_endpoint.
+//
+// The opts are the oauth2/introspection strategy options.
+// The returned function typically used with the token strategy.
+func (p *Provider) GetIntrospectionAuthenticateFunc(opts ...auth.Option) token.AuthenticateFunc {
+	return p.authenticateFunc("introspection_endpoint", func(m *Metadata) string {
+		return m.IntrospectionEndpoint
+	}, func(addr string) token.AuthenticateFunc {
+		return introspection.GetAuthenticateFunc(addr, opts...)
+	})
+}
+
+// NewIntrospection return strategy authenticate request using the metadata introspection_endpoint.
+//
+// The opts are the oauth2/introspection strategy options.
+func (p *Provider) NewIntrospection(c auth.Cache, opts ...auth.Option) auth.Strategy {
+	return token.New(p.GetIntrospectionAuthenticateFunc(opts...), c, opts...)
+}
+
+func (p *Provider) authenticateFunc(
+	name string,
+	endpoint func(*Metadata) string,
+	build func(addr string) token.AuthenticateFunc,
+) token.AuthenticateFunc {
+	d := &discovered{
+		p:        p,
+		name:     name,
+		endpoint: endpoint,
+		build:    build,
+	}
+	return d.authenticate
+}
+
+// discovered authenticate requests using the strategy built from the provider metadata endpoint,
+// and rebuilds the strategy once the endpoint changes.
+type discovered struct {
+	mu       sync.Mutex
+	p        *Provider
+	name     string
+	endpoint func(*Metadata) string
+	build    func(addr string) token.AuthenticateFunc
+	addr     string
+	fn       token.AuthenticateFunc
+}
+
+func (d *discovered) authenticate(ctx context.Context, r *http.Request, tokenstr string) (auth.Info, time.Time, error) { //nolint:lll
+	fn, err := d.get(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return fn(ctx, r, tokenstr)
+}
+
+func (d *discovered) get(ctx context.Context) (token.AuthenticateFunc, error) {
+	m, err := d.p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := d.endpoint(m)
+	if len(addr) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrMissingEndpoint, d.name)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.fn == nil || d.addr != addr {
+		d.addr, d.fn = addr, d.build(addr)
+	}
+
+	return d.fn, nil
+}
@@ -68,6 +68,14 @@ func (c Claims) GetScope() []string {
 	return c.Scope.Split()
 }
 
+// GetConfirmation return's c.Confirmation.
+func (c Claims) GetConfirmation() claims.Confirmation {
+	if c.Standard == nil || c.Confirmation == nil {
+		return claims.Confirmation{}
+	}
+	return *c.Confirmation
+}
+
 type claimsResponse struct {
 	Active bool
 	oauth2.ClaimsResolver
@@ -85,3 +93,8 @@ func (c *claimsResponse) UnmarshalJSON(b []byte) error {
 	c.Active = v.Active
 	return json.Unmarshal(b, &c.ClaimsResolver)
 }
+
+// GetClientID return's c.ClientID.
+func (c Claims) GetClientID() string {
+	return c.ClientID
+}
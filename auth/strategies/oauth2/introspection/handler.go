@@ -0,0 +1,166 @@
+package introspection
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
+)
+
+// Handler return's http.Handler that implements the oauth2 token introspection endpoint,
+// as defined in RFC 7662, on top of any token strategy e.g jwt, opaque, or static tokens.
+//
+// The introspection callers (protected resources) authenticated using the client strategy,
+// and the introspected token authenticated using the s strategy,
+// as a bearer token in the Authorization header by default.
+// Therefore, s should not enforce DPoP or certificate-bound tokens verification.
+//
+// The response is a Claims carrying the token user info and its extensions, scopes, expiry, and confirmation,
+// the go-guardian internal extensions (x-go-guardian-*) not written.
+// The client_id resolved from the user info if it implements GetClientID() string.
+//
+// The WWW-Authenticate challenge of unauthenticated callers resolved from the client strategy,
+// if it implements GetChallenge() string, Otherwise, from the scheme the caller attempted.
+func Handler(s, client auth.Strategy, opts ...auth.Option) http.Handler {
+	h := new(handler)
+	h.strategy = s
+	h.client = client
+	h.typ = token.Bearer
+	for _, opt := range opts {
+		opt.Apply(h)
+	}
+	return h
+}
+
+// internalExtPrefix is the prefix of the extensions used internally by the token strategies.
+const internalExtPrefix = "x-go-guardian-"
+
+// response overrides the claims scope to be a space-separated string as defined in RFC 7662.
+type response struct {
+	Claims
+	Scope string `json:"scope,omitempty"`
+}
+
+type handler struct {
+	strategy auth.Strategy
+	client   auth.Strategy
+	typ      token.Type
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, err := h.client.Authenticate(r.Context(), r); err != nil {
+		if challenge := h.challenge(r); len(challenge) > 0 {
+			w.Header().Set("WWW-Authenticate", challenge)
+		}
+		h.write(w, http.StatusUnauthorized, oauth2.ResponseError{
+			Reason:      "invalid_client",
+			Description: "Client authentication failed",
+		})
+		return
+	}
+
+	tk := r.PostFormValue("token")
+	if len(tk) == 0 {
+		h.write(w, http.StatusBadRequest, oauth2.ResponseError{
+			Reason:      "invalid_request",
+			Description: "Missing token parameter",
+		})
+		return
+	}
+
+	h.write(w, http.StatusOK, h.introspect(r, tk))
+}
+
+func (h *handler) challenge(r *http.Request) string {
+	if v, ok := h.client.(interface{ GetChallenge() string }); ok {
+		return v.GetChallenge()
+	}
+
+	return strings.SplitN(r.Header.Get("Authorization"), " ", 2)[0]
+}
+
+func (h *handler) introspect(r *http.Request, tk string) interface{} {
+	inactive := struct {
+		Active bool `json:"active"`
+	}{}
+
+	// the introspected token not bound to the introspection request,
+	// therefore, authenticate it using a neutral request.
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		return inactive
+	}
+
+	exp := time.Time{}
+	ctx := token.ContextWithExpiresAt(r.Context(), &exp)
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", string(h.typ)+" "+tk)
+
+	info, err := h.strategy.Authenticate(ctx, req)
+	if err != nil {
+		return inactive
+	}
+
+	exts := auth.Extensions{}
+	for k, v := range info.GetExtensions() {
+		if !strings.HasPrefix(k, internalExtPrefix) {
+			exts[k] = v
+		}
+	}
+
+	c := Claims{
+		Active:    true,
+		UserName:  info.GetUserName(),
+		TokenType: string(h.typ),
+		Info:      auth.NewUserInfo(info.GetUserName(), info.GetID(), info.GetGroups(), exts),
+		Standard: &claims.Standard{
+			Subject: info.GetID(),
+		},
+	}
+
+	if v, ok := info.(interface{ GetClientID() string }); ok {
+		c.ClientID = v.GetClientID()
+	}
+
+	if v, ok := info.(interface{ GetExpiresAt() time.Time }); ok && exp.IsZero() {
+		exp = v.GetExpiresAt()
+	}
+
+	if !exp.IsZero() {
+		c.ExpiresAt = (*claims.Time)(&exp)
+	}
+
+	if cnf := token.GetConfirmation(info); cnf != (claims.Confirmation{}) {
+		c.Confirmation = &cnf
+	}
+
+	return response{
+		Claims: c,
+		Scope:  strings.Join(token.GetNamedScopes(info), " "),
+	}
+}
+
+func (h *handler) write(w http.ResponseWriter, code int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
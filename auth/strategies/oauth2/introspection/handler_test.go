@@ -0,0 +1,156 @@
+package introspection
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shaj13/libcache"
+	_ "github.com/shaj13/libcache/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
+)
+
+func TestHandler(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	tokens := token.NewStaticTokens(map[string]token.StaticToken{
+		"valid": {
+			Info:      auth.NewDefaultUser("test", "1", nil, auth.Extensions{"tenant": []string{"acme"}}),
+			Scopes:    []string{"read", "write"},
+			ExpiresAt: exp,
+		},
+	})
+
+	client := basic.New(func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+		if userName == "rs" && password == "secret" {
+			return auth.NewDefaultUser(userName, "rs", nil, nil), nil
+		}
+		return nil, errors.New("invalid credentials")
+	})
+
+	srv := httptest.NewServer(Handler(tokens, client))
+	defer srv.Close()
+
+	table := []struct {
+		name   string
+		method string
+		user   string
+		token  string
+		code   int
+		body   string
+		auth   string
+	}{
+		{
+			name:   "it return 405 when method not post",
+			method: http.MethodGet,
+			user:   "rs",
+			code:   http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "it return 401 when client unauthenticated",
+			method: http.MethodPost,
+			user:   "unknown",
+			token:  "valid",
+			code:   http.StatusUnauthorized,
+			body:   `"error":"invalid_client"`,
+			auth:   "Basic",
+		},
+		{
+			name:   "it return 400 when token missing",
+			method: http.MethodPost,
+			user:   "rs",
+			code:   http.StatusBadRequest,
+			body:   `"error":"invalid_request"`,
+		},
+		{
+			name:   "it return inactive when token invalid",
+			method: http.MethodPost,
+			user:   "rs",
+			token:  "invalid",
+			code:   http.StatusOK,
+			body:   `{"active":false}`,
+		},
+		{
+			name:   "it return active when token valid",
+			method: http.MethodPost,
+			user:   "rs",
+			token:  "valid",
+			code:   http.StatusOK,
+			body:   `"Extensions":{"tenant":["acme"]}},"exp":` + strconv.FormatInt(exp.Unix(), 10) + `,"sub":"1","scope":"read write"}`,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"token": []string{tt.token}}
+			r, _ := http.NewRequest(tt.method, srv.URL, strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.SetBasicAuth(tt.user, "secret")
+
+			resp, err := http.DefaultClient.Do(r)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, _ := ioutil.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			assert.Equal(t, tt.auth, resp.Header.Get("WWW-Authenticate"))
+			assert.Contains(t, string(body), tt.body)
+			assert.NotContains(t, string(body), "x-go-guardian-")
+		})
+	}
+
+	// round trip using the introspection strategy.
+	strategy := New(srv.URL, libcache.LRU.New(0), SetBasicAuth("rs", "secret"))
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer valid")
+
+	info, err := strategy.Authenticate(r.Context(), r)
+	require.NoError(t, err)
+	assert.Equal(t, "1", info.GetID())
+	assert.Equal(t, "test", info.GetUserName())
+	assert.Equal(t, "acme", info.GetExtensions().Get("tenant"))
+	assert.Equal(t, []string{"read", "write"}, token.GetNamedScopes(info))
+	assert.Equal(t, exp.Unix(), info.(Claims).GetExpiresAt().Unix())
+}
+
+func TestHandlerCachedToken(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	shared := auth.NewDefaultUser("test", "1", nil, nil)
+	fn := func(ctx context.Context, r *http.Request, tk string) (auth.Info, time.Time, error) {
+		return shared, exp, nil
+	}
+
+	client := basic.New(func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+		return auth.NewDefaultUser(userName, "rs", nil, nil), nil
+	})
+
+	h := Handler(token.New(fn, libcache.LRU.New(0)), client)
+
+	for i := 0; i < 2; i++ {
+		form := url.Values{"token": []string{"token"}}
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.SetBasicAuth("rs", "secret")
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"exp":`+strconv.FormatInt(exp.Unix(), 10))
+	}
+
+	// the shared info not mutated.
+	assert.Empty(t, shared.GetExtensions())
+}
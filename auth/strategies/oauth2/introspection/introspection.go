@@ -46,7 +46,6 @@ func newIntrospection(addr string, opts ...auth.Option) *introspection {
 		return []byte(u.Encode()), nil
 	}
 	r.SetHeader("Content-Type", "application/x-www-form-urlencoded")
-	r.SetHeader("Accept", "application/json")
 
 	intro := new(introspection)
 	intro.claimResolver = new(Claims)
@@ -59,6 +58,27 @@ func newIntrospection(addr string, opts ...auth.Option) *introspection {
 		opt.Apply(intro)
 	}
 
+	// fail closed rather than silently accepting plain json responses.
+	if intro.jwt != nil && intro.jwt.keeper == nil {
+		intro.err = ErrMissingJWTResponseKeeper
+	}
+
+	if intro.jwt == nil {
+		r.SetHeader("Accept", "application/json")
+		return intro
+	}
+
+	// keep the raw JWT response, to be verified by the jwt response.
+	unmarshal := r.Unmarshal
+	r.Unmarshal = func(data []byte, v interface{}) error {
+		if raw, ok := v.(*[]byte); ok {
+			*raw = data
+			return nil
+		}
+		return unmarshal(data, v)
+	}
+	r.SetHeader("Accept", jwtResponseContentType)
+
 	return intro
 }
 
@@ -67,10 +87,17 @@ type introspection struct {
 	claimResolver oauth2.ClaimsResolver
 	errorResolver oauth2.ErrorResolver
 	requester     *internal.Requester
+	jwt           *jwtResponse
+	err           error
 }
 
 func (i *introspection) authenticate(ctx context.Context, r *http.Request, tokenstr string) (auth.Info, time.Time, error) { //nolint:lll
 	t := time.Time{}
+
+	if i.err != nil {
+		return nil, t, i.err
+	}
+
 	autherr := i.errorResolver.New()
 	authclaims := &claimsResponse{
 		ClaimsResolver: i.claimResolver.New(),
@@ -79,14 +106,32 @@ func (i *introspection) authenticate(ctx context.Context, r *http.Request, token
 	data := url.Values{}
 	data.Add("token", tokenstr)
 
+	var (
+		review interface{} = authclaims
+		raw                = []byte{}
+	)
+
+	if i.jwt != nil {
+		review = &raw
+	}
+
 	//nolint:bodyclose
-	resp, err := i.requester.Do(ctx, data, authclaims, autherr)
+	resp, err := i.requester.Do(ctx, data, review, autherr)
 
 	switch {
 	case err != nil:
 		return nil, t, fmt.Errorf("strategies/oauth2/introspection: %w", err)
 	case resp.StatusCode != http.StatusOK:
 		return nil, t, fmt.Errorf("strategies/oauth2/introspection: %w", autherr)
+	}
+
+	if i.jwt != nil {
+		if err := i.jwt.parse(resp, raw, i.opts, authclaims); err != nil {
+			return nil, t, fmt.Errorf("strategies/oauth2/introspection: %w", err)
+		}
+	}
+
+	switch {
 	case !authclaims.Active:
 		return nil, t, fmt.Errorf("strategies/oauth2/introspection: Token Unauthorized")
 	}
@@ -99,5 +144,6 @@ func (i *introspection) authenticate(ctx context.Context, r *http.Request, token
 	info := claims.Resolve()
 	scope := oauth2.Scope(claims)
 	token.WithNamedScopes(info, scope...)
+	token.WithConfirmation(info, oauth2.Confirmation(claims))
 	return info, oauth2.ExpiresAt(claims), nil
 }
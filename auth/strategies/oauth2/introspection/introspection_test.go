@@ -3,13 +3,26 @@ package introspection
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/internal"
+	ijwt "github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2/clientcredentials"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
 )
 
 func TestIntrospection(t *testing.T) {
@@ -61,6 +74,142 @@ func TestIntrospection(t *testing.T) {
 	}
 }
 
+func TestIntrospectionConfirmation(t *testing.T) {
+	srv := mockAuthzServer(t, "bound_token", 200)
+	fn := GetAuthenticateFunc(srv.URL)
+	info, _, err := fn(context.TODO(), nil, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-x5t", token.GetConfirmation(info).X509Thumbprint)
+}
+
+func TestIntrospectionClientAssertion(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	secret := jwt.StaticSecret{ID: "secret", Secret: []byte("client-secret-client-secret-1234"), Algorithm: jwt.HS256}
+	private := jwt.StaticSecret{ID: "private", Secret: priv, Algorithm: jwt.RS256}
+	public := jwt.StaticSecret{ID: "private", Secret: &priv.PublicKey, Algorithm: jwt.RS256}
+
+	table := []struct {
+		name   string
+		opts   []auth.Option
+		verify jwt.SecretsKeeper
+		aud    string
+		err    string
+	}{
+		{
+			name:   "it authenticate client using private_key_jwt",
+			opts:   []auth.Option{SetPrivateKeyJWT("client", private)},
+			verify: public,
+		},
+		{
+			name:   "it authenticate client using client_secret_jwt",
+			opts:   []auth.Option{SetClientSecretJWT("client", secret)},
+			verify: secret,
+		},
+		{
+			name: "it set client assertion audience",
+			opts: []auth.Option{
+				SetClientAssertionAudience("https://issuer.example.com"),
+				SetPrivateKeyJWT("client", private),
+			},
+			verify: public,
+			aud:    "https://issuer.example.com",
+		},
+		{
+			name: "it return error when private_key_jwt used with hmac secret",
+			opts: []auth.Option{SetPrivateKeyJWT("client", secret)},
+			err:  internal.ErrClientAssertionAlg.Error(),
+		},
+		{
+			name: "it return error when client_secret_jwt used with private key",
+			opts: []auth.Option{SetClientSecretJWT("client", private)},
+			err:  internal.ErrClientAssertionAlg.Error(),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			jtis := map[string]bool{}
+			body, _ := ioutil.ReadFile("./testdata/user_token")
+			var srv *httptest.Server
+
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				assert.Equal(t, "token", r.PostForm.Get("token"))
+				assert.Equal(t, "client", r.PostForm.Get("client_id"))
+				assert.Equal(t, internal.ClientAssertionType, r.PostForm.Get("client_assertion_type"))
+
+				aud := tt.aud
+				if len(aud) == 0 {
+					aud = srv.URL
+				}
+
+				c := claims.Standard{}
+				err := ijwt.ParseToken(tt.verify, r.PostForm.Get("client_assertion"), &c)
+				require.NoError(t, err)
+				assert.NoError(t, c.Verify(claims.VerifyOptions{Audience: claims.StringOrList{aud}, Time: time.Now}))
+				assert.Equal(t, "client", c.Issuer)
+				assert.Equal(t, "client", c.Subject)
+				assert.NotEmpty(t, c.JWTID)
+				assert.False(t, jtis[c.JWTID], "client assertion jti reused")
+				assert.WithinDuration(t, time.Now().Add(time.Minute), time.Time(*c.ExpiresAt), 5*time.Second)
+				jtis[c.JWTID] = true
+
+				w.Write(body)
+			}))
+			defer srv.Close()
+
+			fn := GetAuthenticateFunc(srv.URL, tt.opts...)
+
+			for i := 0; i < 2; i++ {
+				_, _, err := fn(context.TODO(), nil, "token")
+				if len(tt.err) > 0 {
+					assert.Contains(t, err.Error(), tt.err)
+					return
+				}
+				assert.NoError(t, err)
+			}
+
+			assert.Len(t, jtis, 2)
+		})
+	}
+}
+
+func TestIntrospectionTokenSource(t *testing.T) {
+	var issued int32
+
+	body, _ := ioutil.ReadFile("./testdata/user_token")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, n)
+	})
+	mux.HandleFunc("/introspect", func(w http.ResponseWriter, r *http.Request) {
+		// the first issued token revoked.
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "token", r.PostFormValue("token"))
+		w.Write(body)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ts := clientcredentials.New(srv.URL + "/token")
+	fn := GetAuthenticateFunc(srv.URL+"/introspect", SetTokenSource(ts))
+
+	for i := 0; i < 2; i++ {
+		info, _, err := fn(context.TODO(), nil, "token")
+		assert.NoError(t, err)
+		assert.NotNil(t, info)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issued))
+}
+
 func BenchmarkIntrospection(b *testing.B) {
 	r, _ := http.NewRequest("GET", "/", nil)
 	srv := mockAuthzServer(b, "user_token", 200)
@@ -3,11 +3,14 @@ package introspection
 import (
 	"crypto/tls"
 	"net/http"
+	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/claims"
 	"github.com/shaj13/go-guardian/v2/auth/internal"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
 )
 
 // SetBasicAuth sets the introspection request's Authorization header to use
@@ -22,6 +25,13 @@ func SetAuthorizationToken(token string) auth.Option {
 	return internal.SetRequesterBearerToken(token)
 }
 
+// SetTokenSource sets the introspection request's Authorization header to use
+// HTTP Bearer Authentication with a token retrieved from ts, e.g clientcredentials.TokenSource.
+// The request retried once with a fresh token when rejected, if ts implements oauth2.TokenInvalidator.
+func SetTokenSource(ts oauth2.TokenSource) auth.Option {
+	return internal.SetRequesterTokenSource(ts)
+}
+
 // SetHTTPClient sets underlying http client.
 func SetHTTPClient(c *http.Client) auth.Option {
 	return internal.SetRequesterHTTPClient(c)
@@ -37,6 +47,38 @@ func SetClientTransport(rt http.RoundTripper) auth.Option {
 	return internal.SetRequesterClientTransport(rt)
 }
 
+// SetPrivateKeyJWT sets the introspection request's to authenticate the client
+// using private_key_jwt method as defined in RFC 7523 and OpenID Connect core section 9,
+// a fresh client assertion JWT signed with the client private key held by k for each request.
+func SetPrivateKeyJWT(clientid string, k jwt.SecretsKeeper) auth.Option {
+	return internal.SetRequesterClientAssertion(clientid, k, false)
+}
+
+// SetClientSecretJWT sets the introspection request's to authenticate the client
+// using client_secret_jwt method as defined in RFC 7523 and OpenID Connect core section 9,
+// a fresh client assertion JWT signed with the client secret held by k (HMAC) for each request.
+func SetClientSecretJWT(clientid string, k jwt.SecretsKeeper) auth.Option {
+	return internal.SetRequesterClientAssertion(clientid, k, true)
+}
+
+// SetClientAssertionAudience sets the client assertion aud claim.
+// Default the introspection endpoint URL.
+func SetClientAssertionAudience(aud string) auth.Option {
+	return internal.SetRequesterClientAssertionAudience(aud)
+}
+
+// SetClientAssertionLifetime sets the client assertion lifetime.
+// Default 1 minute.
+func SetClientAssertionLifetime(d time.Duration) auth.Option {
+	return internal.SetRequesterClientAssertionLifetime(d)
+}
+
+// SetClientAssertionClock sets the clock used to compute the client assertion iat and exp claims.
+// Default: auth.SystemClock.
+func SetClientAssertionClock(c auth.Clock) auth.Option {
+	return internal.SetRequesterClientAssertionClock(c)
+}
+
 // SetClaimResolver sets the introspection strategy ClaimResolver to resolve
 // the authorization claim response.
 // Default: introspection.Claim
@@ -68,3 +110,66 @@ func SetVerifyOptions(opts claims.VerifyOptions) auth.Option {
 		}
 	})
 }
+
+// SetJWTResponse sets the introspection strategy to request and verify
+// JWT-secured introspection responses as defined in RFC 9701.
+// The response JWT signature verified using k, e.g a JWKS keeper returned by
+// the oauth2/jwt NewSecretsKeeper, and its iss and aud claims must match
+// the given issuer and the resource server client id.
+// Once verified, the token_introspection claim resolved by the ClaimResolver.
+func SetJWTResponse(k jwt.SecretsKeeper, issuer, clientid string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if i, ok := v.(*introspection); ok {
+			if i.jwt == nil {
+				i.jwt = new(jwtResponse)
+			}
+			i.jwt.keeper = k
+			i.jwt.issuer = issuer
+			i.jwt.audience = clientid
+		}
+	})
+}
+
+// SetJWTResponseEncryption sets the secrets keeper that hold the keys to decrypt,
+// the signed then encrypted (nested) JWT-secured introspection responses,
+// The encs are the allowed content encryption algorithms.
+// Default A128CBC-HS256.
+//
+// SetJWTResponseEncryption requires SetJWTResponse,
+// Otherwise, the strategy fails every request with ErrMissingJWTResponseKeeper.
+func SetJWTResponseEncryption(k jwt.SecretsKeeper, encs ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if i, ok := v.(*introspection); ok {
+			if i.jwt == nil {
+				i.jwt = new(jwtResponse)
+			}
+			if len(encs) == 0 {
+				encs = []string{"A128CBC-HS256"}
+			}
+			i.jwt.enc = k
+			i.jwt.encs = encs
+		}
+	})
+}
+
+// SetCertificateBound enables certificate-bound access tokens verification as defined in RFC 8705,
+// the token cnf x5t#S256 claim must match the SHA-256 thumbprint of the request client certificate.
+//
+// SetCertificateBound is similar to:
+//
+// 		token.SetCertificateBound()
+//
+func SetCertificateBound() auth.Option {
+	return token.SetCertificateBound()
+}
+
+// SetTokenType sets the introspection handler token type,
+// used to authenticate the introspected token in the Authorization header.
+// Default: token.Bearer
+func SetTokenType(t token.Type) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*handler); ok {
+			h.typ = t
+		}
+	})
+}
@@ -1,14 +1,19 @@
 package introspection
 
 import (
+	"context"
 	"crypto/tls"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
 	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
 )
 
 func TestSetAuthorizationToken(t *testing.T) {
@@ -57,6 +62,50 @@ func TestSetClientTransport(t *testing.T) {
 	assert.Equal(t, trp, intro.requester.Client.Transport)
 }
 
+func TestSetClientAssertion(t *testing.T) {
+	k := jwt.StaticSecret{}
+	clock := authtest.NewFakeClock(time.Now())
+	intro := newIntrospection(
+		"",
+		SetClientSecretJWT("client", k),
+		SetClientAssertionAudience("aud"),
+		SetClientAssertionLifetime(time.Second),
+		SetClientAssertionClock(clock),
+	)
+	c := intro.requester.ClientAssertion
+	assert.Equal(t, "client", c.ClientID)
+	assert.Equal(t, k, c.Keeper)
+	assert.True(t, c.HMAC)
+	assert.Equal(t, "aud", c.Audience)
+	assert.Equal(t, time.Second, c.Lifetime)
+	assert.Equal(t, clock, c.Clock)
+
+	intro = newIntrospection("", SetPrivateKeyJWT("client", k))
+	c = intro.requester.ClientAssertion
+	assert.False(t, c.HMAC)
+	assert.Equal(t, time.Minute, c.Lifetime)
+	assert.NotNil(t, c.Clock)
+}
+
+func TestSetJWTResponse(t *testing.T) {
+	k := jwt.StaticSecret{}
+	intro := newIntrospection("", SetJWTResponse(k, "iss", "rs"), SetJWTResponseEncryption(k))
+	r, _ := http.NewRequest("", "", nil)
+	intro.requester.AdditionalData(r)
+	assert.Equal(t, "application/token-introspection+jwt", r.Header.Get("Accept"))
+	assert.Equal(t, &jwtResponse{
+		keeper:   k,
+		issuer:   "iss",
+		audience: "rs",
+		enc:      k,
+		encs:     []string{"A128CBC-HS256"},
+	}, intro.jwt)
+
+	intro = newIntrospection("", SetJWTResponseEncryption(k))
+	_, _, err := intro.authenticate(context.TODO(), nil, "token")
+	assert.Equal(t, ErrMissingJWTResponseKeeper, err)
+}
+
 func TestSetErrorResolver(t *testing.T) {
 	err := oauth2.ResponseError{
 		Reason: "test-error",
@@ -83,3 +132,8 @@ func TestSetVerifyOptions(t *testing.T) {
 	intro := newIntrospection("", opt)
 	assert.Equal(t, opts, intro.opts)
 }
+
+func TestSetTokenType(t *testing.T) {
+	h := Handler(nil, nil, SetTokenType(token.APIKey)).(*handler)
+	assert.Equal(t, token.APIKey, h.typ)
+}
@@ -0,0 +1,111 @@
+package introspection
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+)
+
+const (
+	// JWTResponseType represents the JWT-secured introspection response typ header,
+	// as defined in RFC 9701.
+	JWTResponseType = "token-introspection+jwt"
+
+	jwtResponseContentType = "application/" + JWTResponseType
+)
+
+var (
+	// ErrJWTResponseContentType is returned by Authenticate Strategy method,
+	// when JWT-secured introspection response expected but the server returned another content type.
+	ErrJWTResponseContentType = errors.New(
+		"strategies/oauth2/introspection: Expected " + jwtResponseContentType + " response content type",
+	)
+
+	// ErrMissingTokenIntrospection is returned by Authenticate Strategy method,
+	// when JWT-secured introspection response missing the token_introspection claim.
+	ErrMissingTokenIntrospection = errors.New(
+		"strategies/oauth2/introspection: JWT response missing token_introspection claim",
+	)
+
+	// ErrJWTResponseIssuedAt is returned by Authenticate Strategy method,
+	// when JWT-secured introspection response iat claim missing or issued at a future time.
+	ErrJWTResponseIssuedAt = errors.New(
+		"strategies/oauth2/introspection: JWT response iat claim missing or issued at a future time",
+	)
+
+	// ErrMissingJWTResponseKeeper is returned by Authenticate Strategy method,
+	// when SetJWTResponseEncryption provided without SetJWTResponse.
+	ErrMissingJWTResponseKeeper = errors.New(
+		"strategies/oauth2/introspection: JWT response encryption requires SetJWTResponse",
+	)
+)
+
+// jwtResponse verifies JWT-secured introspection responses as defined in RFC 9701.
+type jwtResponse struct {
+	keeper   jwt.SecretsKeeper
+	issuer   string
+	audience string
+	enc      jwt.SecretsKeeper
+	encs     []string
+}
+
+type jwtResponseClaims struct {
+	claims.Standard
+	TokenIntrospection json.RawMessage `json:"token_introspection"`
+}
+
+// parse verifies the response JWT and unmarshal its token_introspection claim into v.
+func (j *jwtResponse) parse(resp *http.Response, body []byte, opts claims.VerifyOptions, v interface{}) error {
+	ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if ct != jwtResponseContentType {
+		return ErrJWTResponseContentType
+	}
+
+	c := jwtResponseClaims{}
+	tstr := string(body)
+
+	var err error
+
+	if j.enc != nil {
+		err = jwt.ParseEncryptedToken(j.keeper, j.enc, nil, JWTResponseType, j.encs, tstr, &c)
+	} else {
+		err = jwt.ParseTypedToken(j.keeper, JWTResponseType, tstr, &c)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// zero time skips time based verification, iat verified below.
+	err = c.Verify(claims.VerifyOptions{
+		Issuer:   j.issuer,
+		Audience: claims.StringOrList{j.audience},
+		Time:     func() time.Time { return time.Time{} },
+	})
+
+	if err != nil {
+		return err
+	}
+
+	clock, leeway := opts.Clock, opts.GetLeeway()
+
+	if clock == nil {
+		clock = auth.SystemClock
+	}
+
+	if c.IssuedAt == nil || time.Time(*c.IssuedAt).After(clock.Now().Add(leeway)) {
+		return ErrJWTResponseIssuedAt
+	}
+
+	if len(c.TokenIntrospection) == 0 {
+		return ErrMissingTokenIntrospection
+	}
+
+	return json.Unmarshal(c.TokenIntrospection, v)
+}
@@ -0,0 +1,219 @@
+package introspection
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/go-jose/go-jose.v2"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	ijwt "github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+	ojwt "github.com/shaj13/go-guardian/v2/auth/strategies/oauth2/jwt"
+)
+
+func TestJWTResponse(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signer := jwt.StaticSecret{ID: "sig", Secret: priv, Algorithm: jwt.ES256}
+	verifier := jwt.StaticSecret{ID: "sig", Secret: &priv.PublicKey, Algorithm: jwt.ES256}
+	enc := jwt.StaticSecret{ID: "enc", Secret: []byte("0123456789abcdef0123456789abcdef"), Algorithm: "A256KW"}
+	now := time.Now()
+	active := map[string]interface{}{"active": true, "sub": "1", "username": "test", "scope": "read"}
+
+	body := func(iat *time.Time, intro interface{}) map[string]interface{} {
+		m := map[string]interface{}{
+			"iss": "https://issuer.example.com",
+			"aud": "rs",
+		}
+		if iat != nil {
+			m["iat"] = iat.Unix()
+		}
+		if intro != nil {
+			m["token_introspection"] = intro
+		}
+		return m
+	}
+
+	future := now.Add(time.Hour)
+
+	table := []struct {
+		name        string
+		opts        []auth.Option
+		jwks        bool
+		contentType string
+		issue       func() (string, error)
+		err         string
+	}{
+		{
+			name: "it return user info from signed response",
+			issue: func() (string, error) {
+				return ijwt.IssueTypedToken(signer, JWTResponseType, body(&now, active))
+			},
+		},
+		{
+			name: "it return user info from signed response verified by jwks",
+			jwks: true,
+			issue: func() (string, error) {
+				return ijwt.IssueTypedToken(signer, JWTResponseType, body(&now, active))
+			},
+		},
+		{
+			name: "it return user info from encrypted response",
+			opts: []auth.Option{SetJWTResponseEncryption(enc, "A256GCM")},
+			issue: func() (string, error) {
+				return ijwt.IssueEncryptedToken(signer, enc, JWTResponseType, "A256GCM", body(&now, active))
+			},
+		},
+		{
+			name: "it return error when encrypted response content encryption not allowed",
+			opts: []auth.Option{SetJWTResponseEncryption(enc)},
+			issue: func() (string, error) {
+				return ijwt.IssueEncryptedToken(signer, enc, JWTResponseType, "A256GCM", body(&now, active))
+			},
+			err: ijwt.ErrUnsupportedAlg.Error(),
+		},
+		{
+			name:        "it return error when response is plain json",
+			contentType: "application/json",
+			issue: func() (string, error) {
+				buf, err := json.Marshal(active)
+				return string(buf), err
+			},
+			err: ErrJWTResponseContentType.Error(),
+		},
+		{
+			name: "it return error when response typ invalid",
+			issue: func() (string, error) {
+				return ijwt.IssueToken(signer, body(&now, active))
+			},
+			err: ijwt.ErrInvalidType.Error(),
+		},
+		{
+			name: "it return error when response issuer mismatch",
+			opts: []auth.Option{SetJWTResponse(verifier, "https://other.example.com", "rs")},
+			issue: func() (string, error) {
+				return ijwt.IssueTypedToken(signer, JWTResponseType, body(&now, active))
+			},
+			err: "claims: standard claims issuer name does not match the expected issuer",
+		},
+		{
+			name: "it return error when response audience mismatch",
+			opts: []auth.Option{SetJWTResponse(verifier, "https://issuer.example.com", "other")},
+			issue: func() (string, error) {
+				return ijwt.IssueTypedToken(signer, JWTResponseType, body(&now, active))
+			},
+			err: "claims: standard claims audience does not have one of the expected audiences",
+		},
+		{
+			name: "it return error when response missing iat",
+			issue: func() (string, error) {
+				return ijwt.IssueTypedToken(signer, JWTResponseType, body(nil, active))
+			},
+			err: ErrJWTResponseIssuedAt.Error(),
+		},
+		{
+			name: "it return error when response issued at a future time",
+			issue: func() (string, error) {
+				return ijwt.IssueTypedToken(signer, JWTResponseType, body(&future, active))
+			},
+			err: ErrJWTResponseIssuedAt.Error(),
+		},
+		{
+			name: "it return error when response missing token_introspection",
+			issue: func() (string, error) {
+				return ijwt.IssueTypedToken(signer, JWTResponseType, body(&now, nil))
+			},
+			err: ErrMissingTokenIntrospection.Error(),
+		},
+		{
+			name: "it return error when token inactive",
+			issue: func() (string, error) {
+				return ijwt.IssueTypedToken(signer, JWTResponseType, body(&now, map[string]bool{"active": false}))
+			},
+			err: "strategies/oauth2/introspection: Token Unauthorized",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.issue()
+			require.NoError(t, err)
+
+			ct := tt.contentType
+			if len(ct) == 0 {
+				ct = jwtResponseContentType
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+					Keys: []jose.JSONWebKey{{Key: &priv.PublicKey, KeyID: "sig", Algorithm: jwt.ES256}},
+				})
+			})
+			mux.HandleFunc("/introspect", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, jwtResponseContentType, r.Header.Get("Accept"))
+				w.Header().Set("Content-Type", ct)
+				_, _ = w.Write([]byte(resp))
+			})
+
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			var k jwt.SecretsKeeper = verifier
+			if tt.jwks {
+				k = ojwt.NewSecretsKeeper(srv.URL + "/jwks")
+			}
+
+			opts := append([]auth.Option{SetJWTResponse(k, "https://issuer.example.com", "rs")}, tt.opts...)
+
+			fn := GetAuthenticateFunc(srv.URL+"/introspect", opts...)
+			info, _, err := fn(context.TODO(), nil, "token")
+
+			if len(tt.err) > 0 {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "test", info.GetUserName())
+			assert.Equal(t, "1", info.GetID())
+		})
+	}
+}
+
+func TestJWTResponseVerifyOptions(t *testing.T) {
+	k := jwt.StaticSecret{ID: "sig", Secret: []byte("response-secret-response-secret!"), Algorithm: jwt.HS256}
+	now := time.Now()
+	tstr, err := ijwt.IssueTypedToken(k, JWTResponseType, map[string]interface{}{
+		"iss":                 "iss",
+		"aud":                 "rs",
+		"iat":                 now.Add(time.Minute * 5).Unix(),
+		"token_introspection": map[string]bool{"active": true},
+	})
+	require.NoError(t, err)
+
+	resp := &http.Response{Header: http.Header{"Content-Type": []string{jwtResponseContentType}}}
+	j := &jwtResponse{keeper: k, issuer: "iss", audience: "rs"}
+	v := &claimsResponse{ClaimsResolver: new(Claims).New()}
+
+	err = j.parse(resp, []byte(tstr), claims.VerifyOptions{}, v)
+	assert.Equal(t, ErrJWTResponseIssuedAt, err)
+
+	leeway := time.Minute * 10
+	err = j.parse(resp, []byte(tstr), claims.VerifyOptions{Leeway: &leeway}, v)
+	assert.NoError(t, err)
+	assert.True(t, v.Active)
+}
@@ -61,6 +61,14 @@ func (c Claims) GetScope() []string {
 	return c.Scope.Split()
 }
 
+// GetConfirmation return's c.Confirmation.
+func (c Claims) GetConfirmation() claims.Confirmation {
+	if c.Standard == nil || c.Confirmation == nil {
+		return claims.Confirmation{}
+	}
+	return *c.Confirmation
+}
+
 // AddressClaim represents a physical mailing address as defined in OpenID
 // https://openid.net/specs/openid-connect-core-1_0.html#AddressClaim.
 type AddressClaim struct {
@@ -151,6 +159,24 @@ func (it IDToken) GetScope() []string {
 	return it.Scope.Split()
 }
 
+// GetConfirmation return's it.Confirmation.
+func (it IDToken) GetConfirmation() claims.Confirmation {
+	if it.Standard == nil || it.Confirmation == nil {
+		return claims.Confirmation{}
+	}
+	return *it.Confirmation
+}
+
+// GetAuthContextRef return's it.AuthContextRef.
+func (it IDToken) GetAuthContextRef() string {
+	return it.AuthContextRef
+}
+
+// GetAuthMethodRef return's it.AuthMethodRef.
+func (it IDToken) GetAuthMethodRef() []string {
+	return it.AuthMethodRef
+}
+
 func pick(candidates ...string) string {
 	for _, c := range candidates {
 		if len(c) > 0 {
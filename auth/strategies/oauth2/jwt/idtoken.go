@@ -0,0 +1,216 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+)
+
+var (
+	// ErrMissingIssuer is returned by IDTokenVerifier Verify method,
+	// when the expected issuer not configured.
+	ErrMissingIssuer = errors.New("strategies/oauth2/jwt: ID token issuer not configured")
+
+	// ErrAuthorizedParty is returned by IDTokenVerifier Verify method,
+	// when the ID token azp claim missing while the token has multiple audiences,
+	// or the azp claim does not match the client id.
+	ErrAuthorizedParty = errors.New("strategies/oauth2/jwt: ID token authorized party does not match the client id")
+
+	// ErrNonceMismatch is returned by IDTokenVerifier Verify method,
+	// when the ID token nonce claim does not match the expected nonce.
+	ErrNonceMismatch = errors.New("strategies/oauth2/jwt: ID token nonce does not match")
+
+	// ErrAccessTokenHash is returned by IDTokenVerifier Verify method,
+	// when the ID token at_hash claim does not match the access token, or missing while required.
+	ErrAccessTokenHash = errors.New("strategies/oauth2/jwt: ID token at_hash does not match the access token")
+
+	// ErrCodeHash is returned by IDTokenVerifier Verify method,
+	// when the ID token c_hash claim does not match the authorization code, or missing while required.
+	ErrCodeHash = errors.New("strategies/oauth2/jwt: ID token c_hash does not match the authorization code")
+
+	// ErrAuthTime is returned by IDTokenVerifier Verify method,
+	// when the ID token auth_time claim required but missing, or the authentication exceeds max age.
+	ErrAuthTime = errors.New("strategies/oauth2/jwt: ID token auth_time missing or exceeds max age")
+)
+
+// IDTokenOptions contains the authentication request parameters for IDTokenVerifier.Verify.
+type IDTokenOptions struct {
+	// Nonce represents the nonce sent in the authentication request,
+	// the ID token nonce claim must match it if not empty.
+	Nonce string
+	// AccessToken represents the access token issued along with the ID token,
+	// the ID token at_hash claim verified against it if both present.
+	AccessToken string
+	// RequireAccessTokenHash requires the ID token at_hash claim when AccessToken not empty,
+	// e.g. when the ID token issued from the authorization endpoint along with the access token.
+	RequireAccessTokenHash bool
+	// Code represents the authorization code issued along with the ID token,
+	// the ID token c_hash claim verified against it if both present.
+	Code string
+	// RequireCodeHash requires the ID token c_hash claim when Code not empty,
+	// e.g. when the ID token issued from the authorization endpoint in the hybrid flow.
+	RequireCodeHash bool
+	// MaxAge represents the max_age sent in the authentication request,
+	// the ID token auth_time claim required and the authentication must not exceed it if not zero.
+	MaxAge time.Duration
+	// RequireAuthTime requires the ID token auth_time claim,
+	// e.g. when requested as an essential claim.
+	RequireAuthTime bool
+}
+
+// IDTokenVerifier verifies OpenID Connect ID tokens,
+// as defined in OpenID Connect Core 1.0 section 3.1.3.7.
+type IDTokenVerifier struct {
+	s        *strategy
+	clientID string
+}
+
+// NewIDTokenVerifier return's new ID token verifier,
+// that verifies ID tokens issued to the given client id against the JWKS at the given address.
+//
+// The expected issuer must be set using SetIssuer or SetVerifyOptions.
+// The opts are the jwt strategy options, except SetClaimResolver and SetAccessTokenProfile.
+func NewIDTokenVerifier(addr, clientID string, opts ...auth.Option) *IDTokenVerifier {
+	return &IDTokenVerifier{
+		s:        newStrategy(addr, opts...),
+		clientID: clientID,
+	}
+}
+
+// Verify verifies the ID token signature and claims,
+// and return's the ID token claims.
+func (v *IDTokenVerifier) Verify(ctx context.Context, tokenstr string, opts IDTokenOptions) (*IDToken, error) {
+	fail := func(err error) (*IDToken, error) {
+		return nil, fmt.Errorf("strategies/oauth2/jwt: %w", err)
+	}
+
+	if len(v.s.opts.Issuer) == 0 {
+		return nil, ErrMissingIssuer
+	}
+
+	it := IDToken{}.New().(*IDToken)
+	h, err := jwt.ParseTokenWithHeader(v.s.jwks, v.s.policy, "", tokenstr, it)
+	if err != nil {
+		return fail(err)
+	}
+
+	vopts := v.s.opts
+	vopts.Audience = []string{v.clientID}
+
+	if err := it.Verify(vopts); err != nil {
+		return fail(err)
+	}
+
+	if it.ExpiresAt == nil || it.IssuedAt == nil {
+		return fail(errors.New("ID token missing exp or iat claim"))
+	}
+
+	if err := v.verify(it, h.Algorithm, opts); err != nil {
+		return nil, err
+	}
+
+	return it, nil
+}
+
+func (v *IDTokenVerifier) verify(it *IDToken, alg string, opts IDTokenOptions) error {
+	equal := func(a, b string) bool {
+		return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+	}
+
+	if (len(it.Audience) > 1 && len(it.AuthorizedParty) == 0) ||
+		(len(it.AuthorizedParty) > 0 && !equal(it.AuthorizedParty, v.clientID)) {
+		return ErrAuthorizedParty
+	}
+
+	if len(opts.Nonce) > 0 && !equal(it.Nonce, opts.Nonce) {
+		return ErrNonceMismatch
+	}
+
+	if len(opts.AccessToken) > 0 && (len(it.AccessTokenHash) > 0 || opts.RequireAccessTokenHash) {
+		if h, err := leftHash(alg, opts.AccessToken); err != nil || !equal(h, it.AccessTokenHash) {
+			return ErrAccessTokenHash
+		}
+	}
+
+	if len(opts.Code) > 0 && (len(it.CodeHash) > 0 || opts.RequireCodeHash) {
+		if h, err := leftHash(alg, opts.Code); err != nil || !equal(h, it.CodeHash) {
+			return ErrCodeHash
+		}
+	}
+
+	if it.AuthTime == nil {
+		if opts.RequireAuthTime || opts.MaxAge > 0 {
+			return ErrAuthTime
+		}
+		return nil
+	}
+
+	clock, leeway := v.s.opts.Clock, v.s.opts.GetLeeway()
+
+	if clock == nil {
+		clock = auth.SystemClock
+	}
+
+	if opts.MaxAge > 0 && clock.Now().Add(-leeway).After(time.Time(*it.AuthTime).Add(opts.MaxAge)) {
+		return ErrAuthTime
+	}
+
+	return nil
+}
+
+// leftHash return's the base64url encoding of the left-most half of the value hash,
+// where the hash algorithm is the one used by the ID token alg header.
+func leftHash(alg, value string) (string, error) {
+	var h crypto.Hash
+
+	switch {
+	case alg == "EdDSA":
+		h = crypto.SHA512
+	case strings.HasSuffix(alg, "256"):
+		h = crypto.SHA256
+	case strings.HasSuffix(alg, "384"):
+		h = crypto.SHA384
+	case strings.HasSuffix(alg, "512"):
+		h = crypto.SHA512
+	default:
+		return "", fmt.Errorf("strategies/oauth2/jwt: Unsupported ID token alg %s", alg)
+	}
+
+	hm := h.New()
+	_, _ = hm.Write([]byte(value))
+	sum := hm.Sum(nil)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
+
+// AuthContextRef return's the authentication context class reference (acr) claim,
+// if info resolved from an ID token, Otherwise, empty string.
+func AuthContextRef(info auth.Info) string {
+	v, ok := info.(interface {
+		GetAuthContextRef() string
+	})
+	if !ok {
+		return ""
+	}
+	return v.GetAuthContextRef()
+}
+
+// AuthMethodRef return's the authentication methods references (amr) claim,
+// if info resolved from an ID token, Otherwise, nil.
+func AuthMethodRef(info auth.Info) []string {
+	v, ok := info.(interface {
+		GetAuthMethodRef() []string
+	})
+	if !ok {
+		return nil
+	}
+	return v.GetAuthMethodRef()
+}
@@ -0,0 +1,226 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+)
+
+func TestIDTokenVerifier(t *testing.T) {
+	srv := mockAuthzServer(t, "jwks.json", nil)
+	defer srv.Close()
+
+	v := NewIDTokenVerifier(srv.URL, "client", SetIssuer("iss"))
+	j := testJwks{v.s.jwks}
+	now := time.Now()
+
+	atHash, err := leftHash("RS256", "token")
+	require.NoError(t, err)
+	cHash, err := leftHash("RS256", "code")
+	require.NoError(t, err)
+
+	table := []struct {
+		name   string
+		claims map[string]interface{}
+		opts   IDTokenOptions
+		err    error
+	}{
+		{
+			name: "it return's id token when valid",
+		},
+		{
+			name:   "it return's error when audience does not contain the client id",
+			claims: map[string]interface{}{"aud": "other"},
+			err:    claims.InvalidError{},
+		},
+		{
+			name:   "it return's error when issuer mismatch",
+			claims: map[string]interface{}{"iss": "other"},
+			err:    claims.InvalidError{},
+		},
+		{
+			name:   "it return's error when expired",
+			claims: map[string]interface{}{"exp": now.Add(-time.Hour).Unix()},
+			err:    claims.InvalidError{},
+		},
+		{
+			name:   "it return's error when iat missing",
+			claims: map[string]interface{}{"iat": nil},
+			err:    errors.New("missing exp or iat"),
+		},
+		{
+			name:   "it return's error when multiple audiences and azp missing",
+			claims: map[string]interface{}{"aud": []string{"client", "other"}},
+			err:    ErrAuthorizedParty,
+		},
+		{
+			name:   "it return's id token when multiple audiences and azp match",
+			claims: map[string]interface{}{"aud": []string{"client", "other"}, "azp": "client"},
+		},
+		{
+			name:   "it return's error when azp does not match",
+			claims: map[string]interface{}{"azp": "other"},
+			err:    ErrAuthorizedParty,
+		},
+		{
+			name:   "it return's id token when nonce match",
+			claims: map[string]interface{}{"nonce": "n-0S6_WzA2Mj"},
+			opts:   IDTokenOptions{Nonce: "n-0S6_WzA2Mj"},
+		},
+		{
+			name:   "it return's error when nonce mismatch",
+			claims: map[string]interface{}{"nonce": "other"},
+			opts:   IDTokenOptions{Nonce: "n-0S6_WzA2Mj"},
+			err:    ErrNonceMismatch,
+		},
+		{
+			name: "it return's error when nonce missing",
+			opts: IDTokenOptions{Nonce: "n-0S6_WzA2Mj"},
+			err:  ErrNonceMismatch,
+		},
+		{
+			name:   "it return's id token when at_hash and c_hash match",
+			claims: map[string]interface{}{"at_hash": atHash, "c_hash": cHash},
+			opts:   IDTokenOptions{AccessToken: "token", Code: "code"},
+		},
+		{
+			name:   "it return's error when at_hash mismatch",
+			claims: map[string]interface{}{"at_hash": atHash},
+			opts:   IDTokenOptions{AccessToken: "other"},
+			err:    ErrAccessTokenHash,
+		},
+		{
+			name:   "it return's error when c_hash mismatch",
+			claims: map[string]interface{}{"c_hash": cHash},
+			opts:   IDTokenOptions{Code: "other"},
+			err:    ErrCodeHash,
+		},
+		{
+			name: "it return's id token when at_hash and c_hash missing and not required",
+			opts: IDTokenOptions{AccessToken: "token", Code: "code"},
+		},
+		{
+			name: "it return's error when at_hash missing and required",
+			opts: IDTokenOptions{AccessToken: "token", RequireAccessTokenHash: true},
+			err:  ErrAccessTokenHash,
+		},
+		{
+			name: "it return's error when c_hash missing and required",
+			opts: IDTokenOptions{Code: "code", RequireCodeHash: true},
+			err:  ErrCodeHash,
+		},
+		{
+			name:   "it return's id token when auth_time within max age",
+			claims: map[string]interface{}{"auth_time": now.Add(-time.Minute).Unix()},
+			opts:   IDTokenOptions{MaxAge: time.Hour},
+		},
+		{
+			name:   "it return's error when auth_time exceeds max age",
+			claims: map[string]interface{}{"auth_time": now.Add(-time.Hour * 2).Unix()},
+			opts:   IDTokenOptions{MaxAge: time.Hour},
+			err:    ErrAuthTime,
+		},
+		{
+			name: "it return's error when auth_time missing and max age requested",
+			opts: IDTokenOptions{MaxAge: time.Hour},
+			err:  ErrAuthTime,
+		},
+		{
+			name: "it return's error when auth_time missing and required",
+			opts: IDTokenOptions{RequireAuthTime: true},
+			err:  ErrAuthTime,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			c := map[string]interface{}{
+				"sub": "test",
+				"iss": "iss",
+				"aud": "client",
+				"exp": now.Add(time.Hour).Unix(),
+				"iat": now.Add(-time.Minute * 5).Unix(),
+			}
+
+			for k, v := range tt.claims {
+				if v == nil {
+					delete(c, k)
+					continue
+				}
+				c[k] = v
+			}
+
+			str, err := jwt.IssueToken(j, c)
+			require.NoError(t, err)
+
+			it, err := v.Verify(context.TODO(), str, tt.opts)
+
+			switch e := tt.err.(type) {
+			case nil:
+				require.NoError(t, err)
+				assert.Equal(t, "test", it.GetID())
+			case claims.InvalidError:
+				assert.True(t, errors.As(err, &e), "got %v", err)
+			default:
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestIDTokenVerifierMissingIssuer(t *testing.T) {
+	v := NewIDTokenVerifier("", "client")
+	_, err := v.Verify(context.TODO(), "", IDTokenOptions{})
+	assert.Equal(t, ErrMissingIssuer, err)
+}
+
+func TestAuthContextRef(t *testing.T) {
+	it := IDToken{
+		AuthContextRef: "urn:mace:incommon:iap:silver",
+		AuthMethodRef:  []string{"pwd", "otp"},
+	}
+
+	table := []struct {
+		name string
+		info auth.Info
+		acr  string
+		amr  []string
+	}{
+		{
+			name: "it return's acr and amr of id token",
+			info: it,
+			acr:  "urn:mace:incommon:iap:silver",
+			amr:  []string{"pwd", "otp"},
+		},
+		{
+			name: "it return's empty acr and amr of other info",
+			info: auth.NewUserInfo("", "", nil, nil),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.acr, AuthContextRef(tt.info))
+			assert.Equal(t, tt.amr, AuthMethodRef(tt.info))
+		})
+	}
+}
+
+func TestLeftHash(t *testing.T) {
+	// test vector from OpenID Connect Core 1.0 appendix A.3.
+	h, err := leftHash("RS256", "jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y")
+	require.NoError(t, err)
+	assert.Equal(t, "77QmUPtjPfzWtF2AnpK9RQ", h)
+
+	_, err = leftHash("none", "value")
+	assert.Error(t, err)
+}
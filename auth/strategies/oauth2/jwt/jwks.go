@@ -10,17 +10,39 @@ import (
 
 	"gopkg.in/go-jose/go-jose.v2"
 
+	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/internal"
 	"github.com/shaj13/go-guardian/v2/auth/internal/header"
+	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
 )
 
 const cacheControl = "cache-control"
 
+// SecretsKeeper hold all secrets/keys to parse JWT token,
+// It's identical to the strategies/jwt SecretsKeeper.
+type SecretsKeeper = jwt.SecretsKeeper
+
+// NewSecretsKeeper return's secrets keeper backed by the JWKS at the given addr,
+// Typically used to verify other JWTs signed by the authorization server,
+// e.g JWT-secured introspection responses.
+//
+// The JWKS fetched lazily and refreshed once its cache-control max-age or the interval elapsed,
+// Accepted options: SetHTTPClient, SetTLSConfig, SetClientTransport, SetInterval, and SetClock.
+func NewSecretsKeeper(addr string, opts ...auth.Option) SecretsKeeper {
+	j := newJWKS(addr)
+	for _, opt := range opts {
+		opt.Apply(j)
+		opt.Apply(j.requester)
+	}
+	return j
+}
+
 type jwks struct {
 	mu        sync.Mutex
 	requester *internal.Requester
 	expiresAt time.Time
 	interval  time.Duration
+	clock     auth.Clock
 	keys      map[string]jose.JSONWebKey
 }
 
@@ -48,7 +70,7 @@ func (j *jwks) load() error {
 	j.mu.Lock()
 	defer j.mu.Unlock()
 
-	if time.Now().UTC().Before(j.expiresAt) {
+	if j.clock.Now().UTC().Before(j.expiresAt) {
 		return nil
 	}
 
@@ -80,12 +102,13 @@ func (j *jwks) setExpiresAt(h http.Header) {
 		}
 	}
 
-	j.expiresAt = time.Now().Add(interval).UTC()
+	j.expiresAt = j.clock.Now().Add(interval).UTC()
 }
 
 func newJWKS(addr string) *jwks {
 	j := new(jwks)
 	j.interval = time.Minute * 5
+	j.clock = auth.SystemClock
 	j.keys = make(map[string]jose.JSONWebKey)
 	j.requester = internal.NewRequester(addr)
 	j.requester.Method = http.MethodGet
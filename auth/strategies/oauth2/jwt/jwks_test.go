@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/v2/auth"
 )
 
 func TestJWKSKID(t *testing.T) {
@@ -78,7 +80,7 @@ func TestJWKSsetExpiresAt(t *testing.T) {
 
 	for _, tt := range table {
 		t.Run(tt.name, func(t *testing.T) {
-			jwks := &jwks{interval: tt.interval}
+			jwks := &jwks{interval: tt.interval, clock: auth.SystemClock}
 			h := http.Header{}
 			h.Set(cacheControl, tt.header)
 			jwks.setExpiresAt(h)
@@ -87,6 +89,19 @@ func TestJWKSsetExpiresAt(t *testing.T) {
 	}
 }
 
+func TestNewSecretsKeeper(t *testing.T) {
+	srv := mockAuthzServer(t, "jwks.json", nil)
+	defer srv.Close()
+
+	k := NewSecretsKeeper(srv.URL, SetInterval(time.Hour))
+	assert.Equal(t, time.Hour, k.(*jwks).interval)
+
+	key, alg, err := k.Get("fdb40e2f9353c58add648b63634e5bbf63e4f502")
+	assert.NoError(t, err)
+	assert.NotNil(t, key)
+	assert.Equal(t, "RS256", alg)
+}
+
 func BenchmarkJWKSLoad(b *testing.B) {
 	counter := 0
 	srv := mockAuthzServer(b, "jwks.json", &counter)
@@ -26,6 +26,18 @@ var (
 	// ErrInvalidAlg is returned by Authenticate Strategy method,
 	// when jwt token alg header does not match key algorithm.
 	ErrInvalidAlg = jwt.ErrInvalidAlg
+
+	// ErrInvalidType is returned by Authenticate Strategy method,
+	// when jwt token typ header does not match the expected type e.g. at+jwt.
+	ErrInvalidType = jwt.ErrInvalidType
+
+	// ErrMissingClaim is returned by Authenticate Strategy method,
+	// when the access token profile enabled and the token missing a required claim.
+	ErrMissingClaim = jwt.ErrMissingClaim
+
+	// ErrMissingAudience is returned by Authenticate Strategy method,
+	// when the access token profile enabled and the audience not configured.
+	ErrMissingAudience = jwt.ErrMissingAudience
 )
 
 // GetAuthenticateFunc return function to authenticate request using oauth2
@@ -61,6 +73,19 @@ func newStrategy(addr string, opts ...auth.Option) *strategy {
 		opt.Apply(strategy.jwks)
 		opt.Apply(strategy.jwks.requester)
 	}
+
+	if strategy.opts.Clock == nil && strategy.clock != nil {
+		strategy.opts.Clock = strategy.clock
+	}
+
+	if strategy.opts.Leeway == nil {
+		strategy.opts.Leeway = strategy.leeway
+	}
+
+	if len(strategy.opts.Issuer) == 0 {
+		strategy.opts.Issuer = strategy.issuer
+	}
+
 	return strategy
 }
 
@@ -68,6 +93,11 @@ type strategy struct {
 	jwks          *jwks
 	opts          claims.VerifyOptions
 	claimResolver oauth2.ClaimsResolver
+	profile       bool
+	policy        *SigningPolicy
+	clock         auth.Clock
+	leeway        *time.Duration
+	issuer        string
 }
 
 func (s *strategy) authenticate(ctx context.Context, r *http.Request, tokenstr string) (auth.Info, time.Time, error) { //nolint:lll
@@ -76,11 +106,23 @@ func (s *strategy) authenticate(ctx context.Context, r *http.Request, tokenstr s
 	}
 
 	claims := s.claimResolver.New()
+	at := jwt.AccessTokenClaims{}
+	typ := ""
 
-	if err := jwt.ParseToken(s.jwks, tokenstr, claims); err != nil {
+	if s.profile {
+		typ = jwt.AccessTokenType
+	}
+
+	if err := jwt.ParseTokenWithPolicy(s.jwks, s.policy, typ, tokenstr, claims, &at); err != nil {
 		return fail(err)
 	}
 
+	if s.profile {
+		if err := at.Verify(s.opts); err != nil {
+			return fail(err)
+		}
+	}
+
 	if err := claims.Verify(s.opts); err != nil {
 		return fail(err)
 	}
@@ -88,6 +130,7 @@ func (s *strategy) authenticate(ctx context.Context, r *http.Request, tokenstr s
 	info := claims.Resolve()
 	scope := oauth2.Scope(claims)
 	token.WithNamedScopes(info, scope...)
+	token.WithConfirmation(info, oauth2.Confirmation(claims))
 
 	return info, oauth2.ExpiresAt(claims), nil
 }
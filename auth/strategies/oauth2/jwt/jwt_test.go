@@ -2,10 +2,12 @@ package jwt
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/shaj13/go-guardian/v2/auth/claims"
 	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
@@ -48,6 +50,121 @@ func Test(t *testing.T) {
 	}
 }
 
+func TestAccessTokenProfile(t *testing.T) {
+	srv := mockAuthzServer(t, "jwks.json", nil)
+	defer srv.Close()
+
+	opts := claims.VerifyOptions{Audience: []string{"rs"}}
+	s := newStrategy(srv.URL, SetAccessTokenProfile(), SetVerifyOptions(opts))
+	j := testJwks{s.jwks}
+
+	exp := claims.Time(time.Now().Add(time.Hour))
+	iat := claims.Time(time.Now().Add(-time.Minute))
+	valid := jwt.AccessTokenClaims{
+		ClientID: "client",
+		Standard: claims.Standard{
+			Subject:   "test",
+			Issuer:    "iss",
+			Audience:  claims.StringOrList{"rs"},
+			ExpiresAt: &exp,
+			IssuedAt:  &iat,
+			JWTID:     "jti",
+		},
+	}
+
+	noClient := valid
+	noClient.ClientID = ""
+
+	otherAud := valid
+	otherAud.Audience = claims.StringOrList{"other"}
+
+	table := []struct {
+		name   string
+		typ    string
+		claims jwt.AccessTokenClaims
+		opts   claims.VerifyOptions
+		err    error
+	}{
+		{
+			name:   "it return's user info when token valid",
+			typ:    jwt.AccessTokenType,
+			claims: valid,
+			opts:   opts,
+		},
+		{
+			name:   "it return's user info when typ has application prefix",
+			typ:    "application/AT+JWT",
+			claims: valid,
+			opts:   opts,
+		},
+		{
+			name:   "it return's error when id token passed as access token",
+			typ:    jwt.TokenType,
+			claims: valid,
+			opts:   opts,
+			err:    ErrInvalidType,
+		},
+		{
+			name:   "it return's error when client_id missing",
+			typ:    jwt.AccessTokenType,
+			claims: noClient,
+			opts:   opts,
+			err:    ErrMissingClaim,
+		},
+		{
+			name:   "it return's error when audience not configured",
+			typ:    jwt.AccessTokenType,
+			claims: valid,
+			err:    ErrMissingAudience,
+		},
+		{
+			name:   "it return's error when audience mismatch",
+			typ:    jwt.AccessTokenType,
+			claims: otherAud,
+			opts:   opts,
+			err:    claims.InvalidError{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s.opts = tt.opts
+			str, err := jwt.IssueTypedToken(j, tt.typ, tt.claims)
+			require.NoError(t, err)
+
+			info, _, err := s.authenticate(context.TODO(), nil, str)
+			if _, ok := tt.err.(claims.InvalidError); ok {
+				assert.True(t, errors.As(err, new(claims.InvalidError)), "got %v", err)
+				return
+			}
+
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), "got %v", err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "test", info.GetID())
+		})
+	}
+}
+
+func TestSigningPolicy(t *testing.T) {
+	srv := mockAuthzServer(t, "jwks.json", nil)
+	defer srv.Close()
+
+	s := newStrategy(srv.URL, SetSigningPolicy(SigningPolicy{Algorithms: []string{"RS256"}, MinRSAKeySize: 2048}))
+	_, _, err := s.authenticate(context.TODO(), nil, generateJWT(t, s.jwks, time.Hour))
+	assert.NoError(t, err)
+
+	s = newStrategy(srv.URL, SetSigningPolicy(SigningPolicy{Algorithms: []string{"ES256"}}))
+	_, _, err = s.authenticate(context.TODO(), nil, generateJWT(t, s.jwks, time.Hour))
+
+	perr := PolicyError{}
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, AlgorithmNotAllowed, perr.Reason)
+}
+
 func generateJWT(tb testing.TB, jwks *jwks, d time.Duration) string {
 	j := testJwks{jwks}
 	exp := claims.Time(time.Now().Add(d))
@@ -9,6 +9,7 @@ import (
 	"github.com/shaj13/go-guardian/v2/auth/claims"
 	"github.com/shaj13/go-guardian/v2/auth/internal"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
 )
 
 // SetHTTPClient sets the underlying http client that used to get JWKS.
@@ -56,3 +57,80 @@ func SetInterval(d time.Duration) auth.Option {
 		}
 	})
 }
+
+// SetClock sets the clock used to verify the jwt claims, refresh JWKS,
+// and compute the cached token ttl.
+// The clock ignored when the verify options sets Time or Clock.
+// Default: auth.SystemClock.
+func SetClock(c auth.Clock) auth.Option {
+	tc := token.SetClock(c)
+	return auth.OptionFunc(func(v interface{}) {
+		switch s := v.(type) {
+		case *strategy:
+			s.clock = c
+		case *jwks:
+			s.clock = c
+		}
+		tc.Apply(v)
+	})
+}
+
+// SetLeeway sets the allowed clock skew when verifying the jwt claims,
+// The leeway ignored when the verify options sets Time or Leeway.
+// Default: claims.DefaultLeeway.
+func SetLeeway(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			s.leeway = &d
+		}
+	})
+}
+
+// SetIssuer sets the expected token issuer when verifying the jwt claims,
+// The issuer ignored when the verify options sets Issuer.
+func SetIssuer(iss string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			s.issuer = iss
+		}
+	})
+}
+
+// SetCertificateBound enables certificate-bound access tokens verification as defined in RFC 8705,
+// the token cnf x5t#S256 claim must match the SHA-256 thumbprint of the request client certificate.
+//
+// SetCertificateBound is similar to:
+//
+// 		token.SetCertificateBound()
+//
+func SetCertificateBound() auth.Option {
+	return token.SetCertificateBound()
+}
+
+// SetAccessTokenProfile validates tokens following
+// the JWT profile for OAuth 2.0 access tokens as defined in RFC 9068.
+// Once set, the token typ header must be at+jwt, therefore ID tokens rejected,
+// and the iss, exp, aud, sub, client_id, iat, and jti claims are required.
+//
+// SetAccessTokenProfile must be used with SetVerifyOptions,
+// to restrict the token audience.
+func SetAccessTokenProfile() auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			s.profile = true
+		}
+	})
+}
+
+// SetSigningPolicy sets the policy to verify the token signing algorithm and JWKS key on each parse,
+// a rejection reported as PolicyError.
+//
+// Once set, JWKS keys without alg are usable as long as the token alg header allowed by the policy,
+// and matches the key type.
+func SetSigningPolicy(p SigningPolicy) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			s.policy = &p
+		}
+	})
+}
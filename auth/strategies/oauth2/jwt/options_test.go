@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
 	"github.com/shaj13/go-guardian/v2/auth/claims"
 )
 
@@ -52,3 +53,51 @@ func TestSetVerifyOptions(t *testing.T) {
 	s := newStrategy("", opt)
 	assert.Equal(t, opts, s.opts)
 }
+
+func TestSetClock(t *testing.T) {
+	clock := authtest.NewFakeClock(time.Now())
+	opt := SetClock(clock)
+	s := newStrategy("", opt)
+	assert.Equal(t, clock, s.opts.Clock)
+	assert.Equal(t, clock, s.jwks.clock)
+
+	// it does not override verify options clock.
+	other := authtest.NewFakeClock(time.Now())
+	s = newStrategy("", opt, SetVerifyOptions(claims.VerifyOptions{Clock: other}))
+	assert.True(t, other == s.opts.Clock)
+}
+
+func TestSetLeeway(t *testing.T) {
+	opt := SetLeeway(time.Second)
+	s := newStrategy("", opt)
+	assert.Equal(t, time.Second, s.opts.GetLeeway())
+
+	// zero leeway disables the clock skew.
+	s = newStrategy("", SetLeeway(0))
+	assert.Equal(t, time.Duration(0), s.opts.GetLeeway())
+
+	// it does not override verify options leeway.
+	leeway := time.Minute
+	s = newStrategy("", opt, SetVerifyOptions(claims.VerifyOptions{Leeway: &leeway}))
+	assert.Equal(t, time.Minute, s.opts.GetLeeway())
+}
+
+func TestSetIssuer(t *testing.T) {
+	s := newStrategy("", SetIssuer("iss"))
+	assert.Equal(t, "iss", s.opts.Issuer)
+
+	// it does not override verify options issuer.
+	s = newStrategy("", SetIssuer("iss"), SetVerifyOptions(claims.VerifyOptions{Issuer: "other"}))
+	assert.Equal(t, "other", s.opts.Issuer)
+}
+
+func TestSetAccessTokenProfile(t *testing.T) {
+	s := newStrategy("", SetAccessTokenProfile())
+	assert.True(t, s.profile)
+}
+
+func TestSetSigningPolicy(t *testing.T) {
+	p := SigningPolicy{AsymmetricOnly: true}
+	s := newStrategy("", SetSigningPolicy(p))
+	assert.Equal(t, &p, s.policy)
+}
@@ -0,0 +1,24 @@
+package jwt
+
+import (
+	"github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+)
+
+// SigningPolicy represents the jwt token signature verification policy,
+// Typically used to restrict the accepted JWKS keys and algorithms.
+type SigningPolicy = jwt.Policy
+
+// PolicyError is returned by Authenticate Strategy method,
+// when the token signing algorithm or JWKS key rejected by the signing policy.
+type PolicyError = jwt.PolicyError
+
+const (
+	// AlgorithmNotAllowed see PolicyError.
+	AlgorithmNotAllowed = jwt.AlgorithmNotAllowed
+	// HMACNotAllowed see PolicyError.
+	HMACNotAllowed = jwt.HMACNotAllowed
+	// KeyTypeMismatch see PolicyError.
+	KeyTypeMismatch = jwt.KeyTypeMismatch
+	// WeakKey see PolicyError.
+	WeakKey = jwt.WeakKey
+)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/internal"
 )
 
 var _ ErrorResolver = ResponseError{}
@@ -27,6 +28,14 @@ type ErrorResolver interface {
 	New() ErrorResolver
 }
 
+// TokenSource supplies the bearer token to authorize the calls to the authorization server endpoints,
+// e.g the token introspection endpoint.
+type TokenSource = internal.TokenSource
+
+// TokenInvalidator is an optional interface that may be implemented by a TokenSource,
+// to discard a token rejected by the server, the rejected call retried once with a fresh token.
+type TokenInvalidator = internal.TokenInvalidator
+
 // ResponseError implements ErrorResolver and provides context information about an
 // authorization error response as defined in RFC 6749.
 type ResponseError struct {
@@ -62,6 +71,19 @@ func ExpiresAt(c ClaimsResolver) time.Time {
 	return e.GetExpiresAt()
 }
 
+// Confirmation returns the result of calling the GetConfirmation method on c,
+// if c type contains an GetConfirmation method returning claims.Confirmation.
+// Otherwise, Confirmation returns zero claims.Confirmation.
+func Confirmation(c ClaimsResolver) claims.Confirmation {
+	s, ok := c.(interface {
+		GetConfirmation() claims.Confirmation
+	})
+	if !ok {
+		return claims.Confirmation{}
+	}
+	return s.GetConfirmation()
+}
+
 // Scope returns the result of calling the GetScope method on c,
 // if c type contains an GetScope method returning slice of strings. Otherwise, Scope returns empty slice.
 func Scope(c ClaimsResolver) []string {
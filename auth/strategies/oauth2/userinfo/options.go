@@ -3,10 +3,12 @@ package userinfo
 import (
 	"crypto/tls"
 	"net/http"
+	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/claims"
 	"github.com/shaj13/go-guardian/v2/auth/internal"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
 )
 
@@ -31,6 +33,40 @@ func SetClientTransport(rt http.RoundTripper) auth.Option {
 	return internal.SetRequesterClientTransport(rt)
 }
 
+// SetPrivateKeyJWT sets the userinfo request's to authenticate the client
+// using private_key_jwt method as defined in RFC 7523 and OpenID Connect core section 9,
+// a fresh client assertion JWT signed with the client private key held by k for each request.
+// The assertion sent in the form body, thus the userinfo request's sent using the POST method.
+func SetPrivateKeyJWT(clientid string, k jwt.SecretsKeeper) auth.Option {
+	return internal.SetRequesterClientAssertion(clientid, k, false)
+}
+
+// SetClientSecretJWT sets the userinfo request's to authenticate the client
+// using client_secret_jwt method as defined in RFC 7523 and OpenID Connect core section 9,
+// a fresh client assertion JWT signed with the client secret held by k (HMAC) for each request.
+// The assertion sent in the form body, thus the userinfo request's sent using the POST method.
+func SetClientSecretJWT(clientid string, k jwt.SecretsKeeper) auth.Option {
+	return internal.SetRequesterClientAssertion(clientid, k, true)
+}
+
+// SetClientAssertionAudience sets the client assertion aud claim.
+// Default the userinfo endpoint URL.
+func SetClientAssertionAudience(aud string) auth.Option {
+	return internal.SetRequesterClientAssertionAudience(aud)
+}
+
+// SetClientAssertionLifetime sets the client assertion lifetime.
+// Default 1 minute.
+func SetClientAssertionLifetime(d time.Duration) auth.Option {
+	return internal.SetRequesterClientAssertionLifetime(d)
+}
+
+// SetClientAssertionClock sets the clock used to compute the client assertion iat and exp claims.
+// Default: auth.SystemClock.
+func SetClientAssertionClock(c auth.Clock) auth.Option {
+	return internal.SetRequesterClientAssertionClock(c)
+}
+
 // SetClaimResolver sets the introspection strategy ClaimResolver to resolve
 // the authorization claim response.
 // Default: introspection.Claim
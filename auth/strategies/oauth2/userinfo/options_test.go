@@ -4,10 +4,13 @@ import (
 	"crypto/tls"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
 	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
 )
 
@@ -38,6 +41,31 @@ func TestSetClientTransport(t *testing.T) {
 	assert.Equal(t, trp, uinfo.requester.Client.Transport)
 }
 
+func TestSetClientAssertion(t *testing.T) {
+	k := jwt.StaticSecret{}
+	clock := authtest.NewFakeClock(time.Now())
+	uinfo := newUserInfo(
+		"",
+		SetClientSecretJWT("client", k),
+		SetClientAssertionAudience("aud"),
+		SetClientAssertionLifetime(time.Second),
+		SetClientAssertionClock(clock),
+	)
+	c := uinfo.requester.ClientAssertion
+	assert.Equal(t, "client", c.ClientID)
+	assert.Equal(t, k, c.Keeper)
+	assert.True(t, c.HMAC)
+	assert.Equal(t, "aud", c.Audience)
+	assert.Equal(t, time.Second, c.Lifetime)
+	assert.Equal(t, clock, c.Clock)
+
+	uinfo = newUserInfo("", SetPrivateKeyJWT("client", k))
+	c = uinfo.requester.ClientAssertion
+	assert.False(t, c.HMAC)
+	assert.Equal(t, time.Minute, c.Lifetime)
+	assert.NotNil(t, c.Clock)
+}
+
 func TestSetErrorResolver(t *testing.T) {
 	err := oauth2.ResponseError{
 		Reason: "test-error",
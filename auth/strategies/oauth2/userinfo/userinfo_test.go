@@ -7,9 +7,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
-
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+	"github.com/shaj13/go-guardian/v2/auth/internal"
+	ijwt "github.com/shaj13/go-guardian/v2/auth/internal/jwt"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/oauth2"
 )
 
@@ -97,6 +105,86 @@ func TestErrorFromHeader(t *testing.T) {
 	}
 }
 
+func TestUserInfoClientAssertion(t *testing.T) {
+	secret := jwt.StaticSecret{ID: "secret", Secret: []byte("client-secret-client-secret-1234"), Algorithm: jwt.HS256}
+
+	table := []struct {
+		name   string
+		method string
+		aud    string
+	}{
+		{
+			name:   "it send client assertion in form body when method is get",
+			method: http.MethodGet,
+		},
+		{
+			name:   "it send client assertion in form body when method is post",
+			method: http.MethodPost,
+		},
+		{
+			name:   "it set client assertion audience",
+			method: http.MethodGet,
+			aud:    "https://issuer.example.com",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			jtis := map[string]bool{}
+			clock := authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+			body := readFile(t, "user_info")
+			var srv *httptest.Server
+
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Empty(t, r.URL.RawQuery)
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+				assert.Equal(t, "client", r.PostForm.Get("client_id"))
+				assert.Equal(t, internal.ClientAssertionType, r.PostForm.Get("client_assertion_type"))
+
+				aud := tt.aud
+				if len(aud) == 0 {
+					aud = srv.URL
+				}
+
+				c := claims.Standard{}
+				require.NoError(t, ijwt.ParseToken(secret, r.PostForm.Get("client_assertion"), &c))
+				assert.Equal(t, claims.StringOrList{aud}, c.Audience)
+				assert.Equal(t, clock.Now().Unix(), time.Time(*c.IssuedAt).Unix())
+				assert.Equal(t, "client", c.Issuer)
+				assert.Equal(t, "client", c.Subject)
+				assert.Equal(t, clock.Now().Add(time.Minute).Unix(), time.Time(*c.ExpiresAt).Unix())
+				assert.NotEmpty(t, c.JWTID)
+				assert.False(t, jtis[c.JWTID], "client assertion jti reused")
+				jtis[c.JWTID] = true
+
+				w.Write(body)
+			}))
+			defer srv.Close()
+
+			opts := []auth.Option{
+				SetHTTPMethod(tt.method),
+				SetClientSecretJWT("client", secret),
+				SetClientAssertionClock(clock),
+			}
+
+			if len(tt.aud) > 0 {
+				opts = append(opts, SetClientAssertionAudience(tt.aud))
+			}
+
+			fn := GetAuthenticateFunc(srv.URL, opts...)
+
+			for i := 0; i < 2; i++ {
+				_, _, err := fn(context.TODO(), nil, "token")
+				assert.NoError(t, err)
+			}
+
+			assert.Len(t, jtis, 2)
+		})
+	}
+}
+
 func BenchmarkUserinfo(b *testing.B) {
 	r, _ := http.NewRequest("GET", "/", nil)
 	srv := mockAuthzServer(b, "user_info", "", 200)
@@ -0,0 +1,303 @@
+package opaque
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+const (
+	opStore  = "store"
+	opRevoke = "revoke"
+)
+
+// FileTokenStore implements the UserTokenStore and persists tokens across restarts,
+// through an append-only log file, while serving lookups from memory.
+//
+// The log compacted on open, and once the stale records (revoked, replaced, or expired tokens)
+// reach the compaction threshold (default 1000).
+//
+// The tokens auth info persisted as JSON and restored using auth.NewUserInfo,
+// Therefore custom auth info types must be registered via auth.SetInfoConstructor.
+//
+// Close must be called to release the log file once the store no longer used.
+type FileTokenStore struct {
+	mu        sync.Mutex
+	path      string
+	file      *os.File
+	mem       *MemoryTokenStore
+	records   int
+	threshold int
+}
+
+// Store stores a new token entry, or replaces the entry with the same signature.
+func (f *FileTokenStore) Store(ctx context.Context, t Token) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.append(opStore, t); err != nil {
+		return err
+	}
+
+	_ = f.mem.Store(ctx, t)
+
+	return f.maybeCompact()
+}
+
+// Lookup return's the token entry by its signature,
+// ErrTokenNotFound returned if the token not found or has expired.
+func (f *FileTokenStore) Lookup(ctx context.Context, signature string) (Token, error) {
+	return f.mem.Lookup(ctx, signature)
+}
+
+// Revoke deletes the token entry by its signature.
+func (f *FileTokenStore) Revoke(ctx context.Context, signature string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.mem.Lookup(ctx, signature); err != nil {
+		return nil
+	}
+
+	if err := f.append(opRevoke, Token{Signature: signature}); err != nil {
+		return err
+	}
+
+	_ = f.mem.Revoke(ctx, signature)
+
+	return f.maybeCompact()
+}
+
+// ListByUser return's the user unexpired token entries, ordered by their lifespan.
+func (f *FileTokenStore) ListByUser(ctx context.Context, userID string) ([]Token, error) {
+	return f.mem.ListByUser(ctx, userID)
+}
+
+// RevokeByUser deletes all the user token entries and return's their signatures.
+func (f *FileTokenStore) RevokeByUser(ctx context.Context, userID string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.mem.mu.Lock()
+	signatures := make([]string, 0, len(f.mem.users[userID]))
+	for sig := range f.mem.users[userID] {
+		signatures = append(signatures, sig)
+	}
+	f.mem.mu.Unlock()
+
+	for i, sig := range signatures {
+		if err := f.append(opRevoke, Token{Signature: sig}); err != nil {
+			return signatures[:i], err
+		}
+		_ = f.mem.Revoke(ctx, sig)
+	}
+
+	return signatures, f.maybeCompact()
+}
+
+// Compact rewrites the log file to hold only the live tokens.
+func (f *FileTokenStore) Compact() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.compact()
+}
+
+// Close stops the background sweeping and closes the log file.
+func (f *FileTokenStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_ = f.mem.Close()
+	return f.file.Close()
+}
+
+func (f *FileTokenStore) append(op string, t Token) error {
+	buf, err := marshalRecord(op, t)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.file.Write(buf); err != nil {
+		return err
+	}
+
+	f.records++
+	return nil
+}
+
+func (f *FileTokenStore) maybeCompact() error {
+	if f.records-f.mem.Len() < f.threshold {
+		return nil
+	}
+	return f.compact()
+}
+
+func (f *FileTokenStore) compact() error {
+	f.mem.Sweep()
+	f.mem.mu.Lock()
+	tokens := make([]Token, 0, len(f.mem.expiry))
+	for _, e := range f.mem.expiry {
+		tokens = append(tokens, e.token)
+	}
+	f.mem.mu.Unlock()
+
+	tmp := f.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	for _, t := range tokens {
+		buf, err := marshalRecord(opStore, t)
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+		_, _ = w.Write(buf)
+	}
+
+	if err := w.Flush(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, f.path); err != nil {
+		return err
+	}
+
+	file, err = os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if f.file != nil {
+		_ = f.file.Close()
+	}
+
+	f.file = file
+	f.records = len(tokens)
+
+	return nil
+}
+
+func (f *FileTokenStore) load() error {
+	file, err := os.Open(f.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	dec := json.NewDecoder(file)
+	ctx := context.Background()
+
+	for {
+		op, t, err := unmarshalRecord(dec)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// a torn write of the last record, the record never acknowledged.
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch op {
+		case opStore:
+			_ = f.mem.Store(ctx, t)
+		case opRevoke:
+			_ = f.mem.Revoke(ctx, t.Signature)
+		}
+	}
+}
+
+// NewFileTokenStore return's new file-backed token store,
+// the tokens loaded from the log file at the given path if exists.
+func NewFileTokenStore(path string, opts ...auth.Option) (*FileTokenStore, error) {
+	f := &FileTokenStore{
+		path:      path,
+		mem:       NewMemoryTokenStore(opts...),
+		threshold: 1000,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(f)
+	}
+
+	if err := f.load(); err != nil {
+		_ = f.mem.Close()
+		return nil, err
+	}
+
+	if err := f.compact(); err != nil {
+		_ = f.mem.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// record represents a log file entry,
+// the outer Info shadows the token Info to hold its raw JSON.
+type record struct {
+	Op string `json:"op"`
+	Token
+	Info json.RawMessage `json:",omitempty"`
+}
+
+func marshalRecord(op string, t Token) ([]byte, error) {
+	r := record{Op: op, Token: t}
+
+	if t.Info != nil {
+		info, err := json.Marshal(t.Info)
+		if err != nil {
+			return nil, err
+		}
+		r.Info = info
+	}
+
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, '\n'), nil
+}
+
+func unmarshalRecord(dec *json.Decoder) (string, Token, error) {
+	r := record{}
+	if err := dec.Decode(&r); err != nil {
+		return "", Token{}, err
+	}
+
+	t := r.Token
+	t.Info = nil
+
+	if len(r.Info) > 0 {
+		info := auth.NewUserInfo("", "", nil, nil)
+		if err := json.Unmarshal(r.Info, info); err != nil {
+			return "", Token{}, err
+		}
+		t.Info = info
+	}
+
+	return r.Op, t, nil
+}
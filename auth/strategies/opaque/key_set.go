@@ -0,0 +1,116 @@
+package opaque
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrKeyNotFound is returned by KeySet, when the key id not found.
+var ErrKeyNotFound = errors.New("strategies/opaque: Key does not exists")
+
+// KeyIDSecretsKeeper is an optional SecretsKeeper extension that identifies keys by their ids,
+// the signing key id embedded in the issued tokens, to verify tokens using only the key they signed by.
+//
+// Keys still used to verify tokens issued without key id.
+type KeyIDSecretsKeeper interface {
+	SecretsKeeper
+	// SigningKey return's the key and its id to sign new tokens,
+	// The key id must not be empty or contain dots.
+	SigningKey() (kid string, key []byte, err error)
+	// KeyByID return's the key by its id.
+	KeyByID(kid string) ([]byte, error)
+}
+
+// KeySet implements the KeyIDSecretsKeeper and holds keys by their ids.
+// It is safe for concurrent use, to rotate keys at runtime.
+type KeySet struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string][]byte
+}
+
+// Keys return's keys to parse tokens issued without key id,
+// the signing key first, followed by the other keys ordered by their ids.
+func (k *KeySet) Keys() ([][]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		if kid != k.active {
+			kids = append(kids, kid)
+		}
+	}
+
+	sort.Strings(kids)
+
+	keys := make([][]byte, 0, len(k.keys))
+	if key, ok := k.keys[k.active]; ok {
+		keys = append(keys, key)
+	}
+
+	for _, kid := range kids {
+		keys = append(keys, k.keys[kid])
+	}
+
+	return keys, nil
+}
+
+// SigningKey return's the key and its id to sign new tokens.
+func (k *KeySet) SigningKey() (string, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[k.active]
+	if !ok {
+		return "", nil, ErrKeyNotFound
+	}
+
+	return k.active, key, nil
+}
+
+// KeyByID return's the key by its id.
+func (k *KeySet) KeyByID(kid string) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+// Add adds a key to only verify tokens signed by it.
+func (k *KeySet) Add(kid string, key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[kid] = key
+}
+
+// Rotate adds a key and sign new tokens by it,
+// the previous signing key kept to verify the tokens signed by it until removed.
+func (k *KeySet) Rotate(kid string, key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[kid] = key
+	k.active = kid
+}
+
+// Remove removes the key by its id,
+// the tokens signed by it no longer verified.
+func (k *KeySet) Remove(kid string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, kid)
+}
+
+// NewKeySet return's new key set, that sign new tokens by the given key.
+func NewKeySet(kid string, key []byte) *KeySet {
+	return &KeySet{
+		active: kid,
+		keys:   map[string][]byte{kid: key},
+	}
+}
@@ -0,0 +1,40 @@
+package opaque
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySet(t *testing.T) {
+	ks := NewKeySet("b", []byte("b"))
+	ks.Add("c", []byte("c"))
+	ks.Add("a", []byte("a"))
+
+	kid, key, err := ks.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "b", kid)
+	assert.Equal(t, []byte("b"), key)
+
+	keys, err := ks.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("a"), []byte("c")}, keys)
+
+	ks.Rotate("d", []byte("d"))
+	kid, _, err = ks.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "d", kid)
+
+	key, err = ks.KeyByID("b")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), key)
+
+	ks.Remove("b")
+	_, err = ks.KeyByID("b")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	ks.Remove("d")
+	_, _, err = ks.SigningKey()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
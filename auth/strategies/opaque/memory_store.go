@@ -0,0 +1,263 @@
+package opaque
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+// ErrTokenNotFound is returned by token stores,
+// when the token not found in the store or has expired.
+var ErrTokenNotFound = errors.New("strategies/opaque: Token does not exists")
+
+// MemoryTokenStore implements the UserTokenStore and holds tokens in-memory.
+//
+// Expired tokens swept periodically in the background (default every minute),
+// And once the store reaches its capacity, the token closest to expiry evicted to make room for the new one.
+//
+// Close must be called to stop the background sweeping once the store no longer used.
+type MemoryTokenStore struct {
+	mu       sync.Mutex
+	tokens   map[string]*entry
+	users    map[string]map[string]*entry
+	expiry   expiryHeap
+	capacity int
+	interval time.Duration
+	clock    auth.Clock
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Store stores a new token entry, or replaces the entry with the same signature.
+func (m *MemoryTokenStore) Store(_ context.Context, t Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store(t)
+	return nil
+}
+
+// Lookup return's the token entry by its signature,
+// ErrTokenNotFound returned if the token not found or has expired.
+func (m *MemoryTokenStore) Lookup(_ context.Context, signature string) (Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.tokens[signature]
+	if !ok {
+		return Token{}, ErrTokenNotFound
+	}
+
+	if e.token.Lifespan.Before(m.clock.Now()) {
+		m.delete(e)
+		return Token{}, ErrTokenNotFound
+	}
+
+	return e.token, nil
+}
+
+// Revoke deletes the token entry by its signature.
+func (m *MemoryTokenStore) Revoke(_ context.Context, signature string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.tokens[signature]; ok {
+		m.delete(e)
+	}
+
+	return nil
+}
+
+// ListByUser return's the user unexpired token entries, ordered by their lifespan.
+func (m *MemoryTokenStore) ListByUser(_ context.Context, userID string) ([]Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	tokens := []Token{}
+
+	for _, e := range m.users[userID] {
+		if !e.token.Lifespan.Before(now) {
+			tokens = append(tokens, e.token)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Lifespan.Before(tokens[j].Lifespan)
+	})
+
+	return tokens, nil
+}
+
+// RevokeByUser deletes all the user token entries and return's their signatures.
+func (m *MemoryTokenStore) RevokeByUser(_ context.Context, userID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	signatures := make([]string, 0, len(m.users[userID]))
+
+	for sig, e := range m.users[userID] {
+		signatures = append(signatures, sig)
+		m.delete(e)
+	}
+
+	return signatures, nil
+}
+
+// Len return's the number of stored tokens, including expired tokens not yet swept.
+func (m *MemoryTokenStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.tokens)
+}
+
+// Sweep deletes all expired tokens.
+func (m *MemoryTokenStore) Sweep() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+}
+
+// Close stops the background sweeping.
+func (m *MemoryTokenStore) Close() error {
+	m.once.Do(func() {
+		close(m.done)
+	})
+	return nil
+}
+
+func (m *MemoryTokenStore) store(t Token) {
+	if e, ok := m.tokens[t.Signature]; ok {
+		m.unindex(e)
+		e.token = t
+		m.index(e)
+		heap.Fix(&m.expiry, e.index)
+		return
+	}
+
+	if m.capacity > 0 && len(m.tokens) >= m.capacity {
+		m.sweep()
+	}
+
+	if m.capacity > 0 && len(m.tokens) >= m.capacity {
+		m.delete(m.expiry[0])
+	}
+
+	e := &entry{token: t}
+	m.tokens[t.Signature] = e
+	m.index(e)
+	heap.Push(&m.expiry, e)
+}
+
+func (m *MemoryTokenStore) index(e *entry) {
+	if e.token.Info == nil {
+		return
+	}
+
+	uid := e.token.Info.GetID()
+	if _, ok := m.users[uid]; !ok {
+		m.users[uid] = make(map[string]*entry)
+	}
+
+	m.users[uid][e.token.Signature] = e
+}
+
+func (m *MemoryTokenStore) unindex(e *entry) {
+	if e.token.Info == nil {
+		return
+	}
+
+	uid := e.token.Info.GetID()
+	delete(m.users[uid], e.token.Signature)
+	if len(m.users[uid]) == 0 {
+		delete(m.users, uid)
+	}
+}
+
+func (m *MemoryTokenStore) sweep() {
+	now := m.clock.Now()
+	for len(m.expiry) > 0 && m.expiry[0].token.Lifespan.Before(now) {
+		m.delete(m.expiry[0])
+	}
+}
+
+func (m *MemoryTokenStore) delete(e *entry) {
+	delete(m.tokens, e.token.Signature)
+	m.unindex(e)
+	heap.Remove(&m.expiry, e.index)
+}
+
+func (m *MemoryTokenStore) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.Sweep()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// NewMemoryTokenStore return's new in-memory token store.
+func NewMemoryTokenStore(opts ...auth.Option) *MemoryTokenStore {
+	m := &MemoryTokenStore{
+		tokens:   make(map[string]*entry),
+		users:    make(map[string]map[string]*entry),
+		interval: time.Minute,
+		clock:    auth.SystemClock,
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt.Apply(m)
+	}
+
+	if m.interval > 0 {
+		go m.run()
+	}
+
+	return m
+}
+
+type entry struct {
+	token Token
+	index int
+}
+
+// expiryHeap implements heap.Interface and orders tokens by their lifespan,
+// the token closest to expiry first.
+type expiryHeap []*entry
+
+func (h expiryHeap) Len() int { return len(h) }
+
+func (h expiryHeap) Less(i, j int) bool {
+	return h[i].token.Lifespan.Before(h[j].token.Lifespan)
+}
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
@@ -5,6 +5,10 @@
 // information in a server's persistent storage.
 //
 // It uses HMAC with SHA to generate and validate tokens.
+//
+// Tokens signed by a KeyIDSecretsKeeper key embed the key id,
+// in the format <prefix>.v2.<key id>.<payload>, and verified using only that key.
+// Otherwise, in the legacy format <prefix>.<payload>, and verified using all the keeper keys.
 package opaque
 
 import (
@@ -12,17 +16,27 @@ import (
 	"crypto"
 	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
 )
 
+// tokenVersion is the version of the token format that embeds the key id.
+const tokenVersion = "v2"
+
+// ErrRetiredKeyID is returned when parsing token signed by a retired key id,
+// See WithRetiredKeyIDs.
+var ErrRetiredKeyID = errors.New("strategies/opaque: Token signed by retired key")
+
 // SecretsKeeper hold all secrets/keys to sign and parse opaque token.
 type SecretsKeeper interface {
 	// Keys return's keys to sign and parse opaque token,
@@ -53,6 +67,14 @@ type TokenStore interface {
 type Token struct {
 	// Lifespan represent when the token expires.
 	Lifespan time.Time
+	// MaxLifespan represent the absolute time the token expires,
+	// when sliding expiration enabled, the Lifespan renewed on the token use up to MaxLifespan.
+	//
+	// Zero MaxLifespan means the token Lifespan never renewed.
+	MaxLifespan time.Time
+	// KeyID represent the id of the key the token signed by,
+	// Empty if the token issued without key id.
+	KeyID string
 	// Signature a unique HMAC, per token.
 	//
 	// Signature used to verify client token.
@@ -64,6 +86,19 @@ type Token struct {
 	Prefix string
 	// Info represent auth info token is mapped to it.
 	Info auth.Info
+	// CreatedAt represent when the token issued.
+	CreatedAt time.Time
+	// LastUsedAt represent when the token last used,
+	// updated only when last-used tracking enabled (see WithLastUsedInterval).
+	//
+	// Zero LastUsedAt means the token never used or not tracked.
+	LastUsedAt time.Time
+	// ClientIP represent the IP address of the client the token issued to (see WithRequest).
+	ClientIP string
+	// UserAgent represent the user agent of the client the token issued to (see WithRequest).
+	UserAgent string
+	// Device represent an optional label of the client device (see WithDeviceLabel).
+	Device string
 }
 
 // IssueToken issue token for the provided user info.
@@ -89,9 +124,23 @@ func GetAuthenticateFunc(s TokenStore, k SecretsKeeper, opts ...auth.Option) tok
 //
 //	fn := opaque.GetAuthenticateFunc(tokenStore, secretsKeeper, opts...)
 //	token.New(fn, cache, opts...)
+//
+// Except the tokens cached by their signatures, to be invalidated by RevokeUserTokens and RevokeTokenSignature,
+// which verifies the token signature on each request, even when the token cached.
+// A caller provided token.SetHash or token.SetHasher overrides the signatures cache keys,
+// Then RevokeUserTokens and RevokeTokenSignature does not invalidate the cached tokens.
+// And when sliding expiration or last-used tracking enabled, the tokens cached up to
+// the renewal or last-used interval, to renew their lifespan and update their last used time on use.
 func New(c auth.Cache, s TokenStore, k SecretsKeeper, opts ...auth.Option) auth.Strategy {
-	fn := GetAuthenticateFunc(s, k, opts...)
-	return token.New(fn, c, opts...)
+	o := newOpaque(s, k, opts...)
+	// prepend the signature hasher, so a caller provided hasher overrides it.
+	opts = append([]auth.Option{token.SetHasher(o.key)}, opts...)
+
+	if ttl := o.cacheTTL(); ttl > 0 {
+		c = renewalCache{Cache: c, interval: ttl}
+	}
+
+	return token.New(o.parse, c, opts...)
 }
 
 func newOpaque(s TokenStore, k SecretsKeeper, opts ...auth.Option) *opaque {
@@ -102,12 +151,21 @@ func newOpaque(s TokenStore, k SecretsKeeper, opts ...auth.Option) *opaque {
 		keeper:      k,
 		store:       s,
 		h:           crypto.SHA512_256,
+		clock:       auth.SystemClock,
 	}
 
 	for _, opt := range opts {
 		opt.Apply(o)
 	}
 
+	if o.absolute == 0 {
+		o.absolute = o.exp
+	}
+
+	if o.interval == 0 {
+		o.interval = o.idle / 10
+	}
+
 	return o
 }
 
@@ -118,6 +176,15 @@ type opaque struct {
 	keeper      SecretsKeeper
 	store       TokenStore
 	h           crypto.Hash
+	clock       auth.Clock
+	idle        time.Duration
+	absolute    time.Duration
+	interval    time.Duration
+	lastUsed    time.Duration
+	r           *http.Request
+	ipHeader    string
+	device      string
+	retired     map[string]bool
 }
 
 func (o *opaque) issue(ctx context.Context, info auth.Info) (string, error) {
@@ -126,46 +193,206 @@ func (o *opaque) issue(ctx context.Context, info auth.Info) (string, error) {
 		return "", err
 	}
 
-	keys, err := o.keeper.Keys()
-
-	if len(keys) == 0 || err != nil {
-		return "", fmt.Errorf("strategies/opaque: no key to sign token %w", err)
+	kid, key, err := o.signingKey()
+	if err != nil {
+		return "", err
 	}
 
-	signature := o.sign(keys[0], id)
+	signature := o.sign(key, kid, id)
+	now := o.clock.Now()
 
 	t := Token{
 		Prefix:    o.prefix,
-		Lifespan:  time.Now().Add(o.exp),
+		Lifespan:  now.Add(o.exp),
 		Info:      info,
+		KeyID:     kid,
 		Signature: base64.RawURLEncoding.EncodeToString(signature),
+		CreatedAt: now,
+		Device:    o.device,
+	}
+
+	if o.r != nil {
+		t.ClientIP = clientIP(o.r, o.ipHeader)
+		t.UserAgent = o.r.UserAgent()
+	}
+
+	if o.idle > 0 {
+		t.MaxLifespan = now.Add(o.absolute)
+		t.Lifespan = o.lifespan(now, t.MaxLifespan)
 	}
 
 	if err := o.store.Store(ctx, t); err != nil {
 		return "", err
 	}
 
-	mixed := append(id, signature...)
-	return o.prefix + "." + base64.RawURLEncoding.EncodeToString(mixed), nil
+	mixed := base64.RawURLEncoding.EncodeToString(append(id, signature...))
+
+	if len(kid) > 0 {
+		return o.prefix + "." + tokenVersion + "." + kid + "." + mixed, nil
+	}
+
+	return o.prefix + "." + mixed, nil
+}
+
+// signingKey return's the key and its id to sign new tokens,
+// the key id is empty if the secrets keeper does not implement KeyIDSecretsKeeper.
+func (o *opaque) signingKey() (string, []byte, error) {
+	kk, ok := o.keeper.(KeyIDSecretsKeeper)
+	if !ok {
+		keys, err := o.keeper.Keys()
+		if len(keys) == 0 || err != nil {
+			return "", nil, fmt.Errorf("strategies/opaque: no key to sign token %w", err)
+		}
+		return "", keys[0], nil
+	}
+
+	kid, key, err := kk.SigningKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("strategies/opaque: no key to sign token %w", err)
+	}
+
+	if len(kid) == 0 || strings.Contains(kid, ".") {
+		return "", nil, fmt.Errorf("strategies/opaque: invalid key id %q", kid)
+	}
+
+	return kid, key, nil
+}
+
+// verificationKeys return's the keys to verify token signed by the given key id,
+// or all the keeper keys if the token issued without key id.
+func (o *opaque) verificationKeys(kid string, versioned bool) ([][]byte, error) {
+	if !versioned {
+		keys, err := o.keeper.Keys()
+		if len(keys) == 0 || err != nil {
+			return nil, fmt.Errorf("strategies/opaque: no key to sign token %w", err)
+		}
+		return keys, nil
+	}
+
+	if o.retired[kid] {
+		return nil, ErrRetiredKeyID
+	}
+
+	kk, ok := o.keeper.(KeyIDSecretsKeeper)
+	if !ok {
+		return nil, errors.New("strategies/opaque: secrets keeper does not support key ids")
+	}
+
+	key, err := kk.KeyByID(kid)
+	if err != nil {
+		return nil, fmt.Errorf("strategies/opaque: no key to verify token %w", err)
+	}
+
+	return [][]byte{key}, nil
 }
 
 func (o *opaque) parse(ctx context.Context, _ *http.Request, token string) (auth.Info, time.Time, error) {
+	signature, err := o.verify(token)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	t, err := o.store.Lookup(ctx, signature)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if t.Lifespan.Before(o.clock.Now()) {
+		return nil, time.Time{}, errors.New("strategies/opaque: token is expired")
+	}
+
+	r, renewed := o.renew(t)
+	r, touched := o.touch(r)
+
+	// the token remains valid until its current lifespan, if the renewal fails.
+	if (renewed || touched) && o.store.Store(ctx, r) == nil {
+		t = r
+	}
+
+	return t.Info, t.Lifespan, nil
+}
+
+// renew slides the token lifespan by the idle timeout up to its max lifespan,
+// and return's false if sliding expiration disabled or the renewal not yet due.
+func (o *opaque) renew(t Token) (Token, bool) {
+	if o.idle == 0 || t.MaxLifespan.IsZero() {
+		return t, false
+	}
+
+	exp := o.lifespan(o.clock.Now(), t.MaxLifespan)
+
+	// renew at most once per interval, except the last renewal up to the max lifespan.
+	if !exp.After(t.Lifespan) || (exp.Sub(t.Lifespan) < o.interval && !exp.Equal(t.MaxLifespan)) {
+		return t, false
+	}
+
+	t.Lifespan = exp
+	return t, true
+}
+
+// touch updates the token last used time,
+// and return's false if last-used tracking disabled or the update not yet due.
+func (o *opaque) touch(t Token) (Token, bool) {
+	now := o.clock.Now()
+
+	if o.lastUsed == 0 || now.Sub(t.LastUsedAt) < o.lastUsed {
+		return t, false
+	}
+
+	t.LastUsedAt = now
+	return t, true
+}
+
+// cacheTTL return's the maximum ttl of the cached tokens,
+// to renew the tokens and update their last used time while in use.
+func (o *opaque) cacheTTL() time.Duration {
+	ttl := o.interval
+	if o.idle == 0 || (o.lastUsed > 0 && o.lastUsed < ttl) {
+		ttl = o.lastUsed
+	}
+	return ttl
+}
+
+func (o *opaque) lifespan(now, max time.Time) time.Time {
+	if exp := now.Add(o.idle); exp.Before(max) {
+		return exp
+	}
+	return max
+}
+
+// verify verifies the token signature and return's it encoded as held by the token store.
+func (o *opaque) verify(token string) (string, error) {
 	if len(token) <= (len(o.prefix) + o.tokenLength + 1) {
-		return nil, time.Time{}, errors.New("strategies/opaque: token is too short")
+		return "", errors.New("strategies/opaque: token is too short")
 	}
 
 	if token[:len(o.prefix)] != o.prefix {
-		return nil, time.Time{}, errors.New("strategies/opaque: invalid token prefix")
+		return "", errors.New("strategies/opaque: invalid token prefix")
 	}
 
-	mixed, err := base64.RawURLEncoding.DecodeString(token[len(o.prefix)+1:])
+	payload := token[len(o.prefix)+1:]
+	kid, versioned := "", strings.HasPrefix(payload, tokenVersion+".")
+
+	if versioned {
+		i := strings.LastIndexByte(payload, '.')
+		if i <= len(tokenVersion) {
+			return "", errors.New("strategies/opaque: token is missing key id")
+		}
+		kid, payload = payload[len(tokenVersion)+1:i], payload[i+1:]
+	}
+
+	mixed, err := base64.RawURLEncoding.DecodeString(payload)
 	if err != nil {
-		return nil, time.Time{}, err
+		return "", err
+	}
+
+	if len(mixed) <= o.tokenLength {
+		return "", errors.New("strategies/opaque: token is too short")
 	}
 
-	keys, err := o.keeper.Keys()
-	if len(keys) == 0 || err != nil {
-		return nil, time.Time{}, fmt.Errorf("strategies/opaque: no key to sign token %w", err)
+	keys, err := o.verificationKeys(kid, versioned)
+	if err != nil {
+		return "", err
 	}
 
 	id := mixed[:o.tokenLength]
@@ -173,30 +400,68 @@ func (o *opaque) parse(ctx context.Context, _ *http.Request, token string) (auth
 	ok := false
 
 	for _, key := range keys {
-		mac := o.sign(key, id)
+		mac := o.sign(key, kid, id)
 		if ok = hmac.Equal(mac, signature); ok {
 			break
 		}
 	}
 
 	if !ok {
-		return nil, time.Time{}, errors.New("strategies/opaque: invalid token signature")
+		return "", errors.New("strategies/opaque: invalid token signature")
 	}
 
-	t, err := o.store.Lookup(ctx, base64.RawURLEncoding.EncodeToString(signature))
-	if err != nil {
-		return nil, time.Time{}, err
+	return base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// key return's the token cache key, which is the token signature,
+// to be able to invalidate cached tokens by their signatures.
+// Tokens with invalid signature never cached, and their key derived from the token hash,
+// to never match a signature.
+func (o *opaque) key(token string) string {
+	if signature, err := o.verify(token); err == nil {
+		return signature
 	}
 
-	if t.Lifespan.Before(time.Now()) {
-		return nil, time.Time{}, errors.New("strategies/opaque: token is expired")
+	sum := sha256.Sum256([]byte(token))
+	return "!" + base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// renewalCache caps the cached tokens ttl to the renewal or last-used interval,
+// so the tokens parsed while in use.
+type renewalCache struct {
+	auth.Cache
+	interval time.Duration
+}
+
+func (c renewalCache) StoreWithTTL(key interface{}, value interface{}, ttl time.Duration) {
+	if ttl > c.interval {
+		ttl = c.interval
 	}
+	c.Cache.StoreWithTTL(key, value, ttl)
+}
 
-	return t.Info, t.Lifespan, nil
+// clientIP return's the request client IP address,
+// from the last value of the given header when present, otherwise the request remote address.
+func clientIP(r *http.Request, header string) string {
+	if v := r.Header.Get(header); len(header) > 0 && len(v) > 0 {
+		ips := strings.Split(v, ",")
+		return strings.TrimSpace(ips[len(ips)-1])
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
 }
 
-func (o *opaque) sign(key, id []byte) []byte {
+// sign return's the token id HMAC, bound to the key id if not empty.
+func (o *opaque) sign(key []byte, kid string, id []byte) []byte {
 	hm := hmac.New(o.h.New, key)
+	if len(kid) > 0 {
+		_, _ = hm.Write([]byte(tokenVersion + "." + kid + "."))
+	}
 	_, _ = hm.Write(id)
 	return hm.Sum(nil)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
 )
 
 func TestEverything(t *testing.T) {
@@ -76,6 +78,402 @@ func TestIssue(t *testing.T) {
 	}
 }
 
+func TestParseClock(t *testing.T) {
+	k := StaticSecret([]byte("test"))
+	s := &testStore{}
+	clock := authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	opts := []auth.Option{WithClock(clock), WithExpDuration(time.Hour)}
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+
+	token, err := IssueToken(context.TODO(), info, s, k, opts...)
+	require.NoError(t, err)
+	require.Equal(t, clock.Now().Add(time.Hour), s.t.Lifespan)
+
+	fn := GetAuthenticateFunc(s, k, opts...)
+	_, _, err = fn(context.TODO(), nil, token)
+	require.NoError(t, err)
+
+	clock.Advance(time.Hour * 2)
+	_, _, err = fn(context.TODO(), nil, token)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "token is expired")
+}
+
+func TestSlidingExpiration(t *testing.T) {
+	k := StaticSecret([]byte("test"))
+	clock := authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	start := clock.Now()
+	s := NewMemoryTokenStore(WithClock(clock), WithSweepInterval(0))
+	opts := []auth.Option{
+		WithClock(clock),
+		WithIdleTimeout(time.Minute * 10),
+		WithAbsoluteLifetime(time.Minute * 20),
+		WithRenewInterval(time.Minute),
+	}
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+
+	token, err := IssueToken(context.TODO(), info, s, k, opts...)
+	require.NoError(t, err)
+
+	fn := GetAuthenticateFunc(s, k, opts...)
+	parse := func() (time.Time, error) {
+		_, exp, err := fn(context.TODO(), nil, token)
+		return exp, err
+	}
+
+	table := []struct {
+		name    string
+		advance time.Duration
+		exp     time.Time
+		err     bool
+	}{
+		{
+			name: "it issue token with idle timeout lifespan",
+			exp:  start.Add(time.Minute * 10),
+		},
+		{
+			name:    "it throttle the renewal within the renew interval",
+			advance: time.Second * 30,
+			exp:     start.Add(time.Minute * 10),
+		},
+		{
+			name:    "it renew the token lifespan on use",
+			advance: time.Minute * 5,
+			exp:     start.Add(time.Second*330 + time.Minute*10),
+		},
+		{
+			name:    "it renew the token lifespan up to the absolute lifetime",
+			advance: time.Minute * 9,
+			exp:     start.Add(time.Minute * 20),
+		},
+		{
+			name:    "it does not renew the token lifespan beyond the absolute lifetime",
+			advance: time.Minute * 5,
+			exp:     start.Add(time.Minute * 20),
+		},
+		{
+			name:    "it expire the token after the absolute lifetime",
+			advance: time.Minute,
+			err:     true,
+		},
+	}
+
+	for _, tt := range table {
+		clock.Advance(tt.advance)
+		exp, err := parse()
+
+		if tt.err {
+			require.Error(t, err, tt.name)
+			continue
+		}
+
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.exp, exp, tt.name)
+
+		// it write the renewal through the token store.
+		got, err := s.Lookup(context.TODO(), tokenSignature(t, k, token))
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.exp, got.Lifespan, tt.name)
+		require.Equal(t, start.Add(time.Minute*20), got.MaxLifespan, tt.name)
+	}
+}
+
+func TestSlidingExpirationIdle(t *testing.T) {
+	k := StaticSecret([]byte("test"))
+	clock := authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewMemoryTokenStore(WithClock(clock), WithSweepInterval(0))
+	opts := []auth.Option{WithClock(clock), WithIdleTimeout(time.Minute * 10)}
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+
+	token, err := IssueToken(context.TODO(), info, s, k, opts...)
+	require.NoError(t, err)
+
+	clock.Advance(time.Minute * 11)
+	_, _, err = GetAuthenticateFunc(s, k, opts...)(context.TODO(), nil, token)
+	require.Error(t, err)
+}
+
+func TestSlidingExpirationCacheTTL(t *testing.T) {
+	k := StaticSecret([]byte("test"))
+	s := NewMemoryTokenStore(WithSweepInterval(0))
+	c := &ttlCache{Cache: libcache.LRU.New(0)}
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+
+	table := []struct {
+		name string
+		opts []auth.Option
+		ttl  time.Duration
+	}{
+		{
+			name: "it cache token until its lifespan when sliding expiration disabled",
+			opts: []auth.Option{WithExpDuration(time.Hour)},
+			ttl:  time.Hour,
+		},
+		{
+			name: "it cache token up to the renew interval when sliding expiration enabled",
+			opts: []auth.Option{WithIdleTimeout(time.Hour), WithRenewInterval(time.Minute)},
+			ttl:  time.Minute,
+		},
+		{
+			name: "it cache token up to the last-used interval when last-used tracking enabled",
+			opts: []auth.Option{WithExpDuration(time.Hour), WithLastUsedInterval(time.Second * 30)},
+			ttl:  time.Second * 30,
+		},
+		{
+			name: "it cache token up to the shortest interval",
+			opts: []auth.Option{
+				WithIdleTimeout(time.Hour),
+				WithRenewInterval(time.Minute),
+				WithLastUsedInterval(time.Minute * 5),
+			},
+			ttl: time.Minute,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := IssueToken(context.TODO(), info, s, k, tt.opts...)
+			require.NoError(t, err)
+
+			r, _ := http.NewRequest("", "", nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+
+			_, err = New(c, s, k, tt.opts...).Authenticate(r.Context(), r)
+			require.NoError(t, err)
+			require.InDelta(t, tt.ttl, c.ttl, float64(time.Second))
+		})
+	}
+}
+
+func TestIssueMetadata(t *testing.T) {
+	k := StaticSecret([]byte("test"))
+	clock := authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+
+	table := []struct {
+		name   string
+		opts   []auth.Option
+		header string
+		ip     string
+		agent  string
+		device string
+	}{
+		{
+			name: "it record nothing but creation time without request",
+		},
+		{
+			name:  "it record client ip and user agent from request",
+			ip:    "192.0.2.1",
+			agent: "test-agent/1.0",
+		},
+		{
+			name:   "it record device label",
+			opts:   []auth.Option{WithDeviceLabel("laptop")},
+			device: "laptop",
+		},
+		{
+			name:   "it record client ip from the last header value",
+			opts:   []auth.Option{WithClientIPHeader("X-Forwarded-For")},
+			header: "198.51.100.1, 203.0.113.1",
+			ip:     "203.0.113.1",
+			agent:  "test-agent/1.0",
+		},
+		{
+			name:  "it record client ip from remote address when header missing",
+			opts:  []auth.Option{WithClientIPHeader("X-Forwarded-For")},
+			ip:    "192.0.2.1",
+			agent: "test-agent/1.0",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &testStore{}
+			opts := append([]auth.Option{WithClock(clock)}, tt.opts...)
+
+			if len(tt.ip) > 0 {
+				r, _ := http.NewRequest("", "", nil)
+				r.RemoteAddr = "192.0.2.1:1234"
+				r.Header.Set("User-Agent", tt.agent)
+				if len(tt.header) > 0 {
+					r.Header.Set("X-Forwarded-For", tt.header)
+				}
+				opts = append(opts, WithRequest(r))
+			}
+
+			_, err := IssueToken(context.TODO(), info, s, k, opts...)
+			require.NoError(t, err)
+			require.Equal(t, clock.Now(), s.t.CreatedAt)
+			require.True(t, s.t.LastUsedAt.IsZero())
+			require.Equal(t, tt.ip, s.t.ClientIP)
+			require.Equal(t, tt.agent, s.t.UserAgent)
+			require.Equal(t, tt.device, s.t.Device)
+		})
+	}
+}
+
+func TestLastUsed(t *testing.T) {
+	k := StaticSecret([]byte("test"))
+	clock := authtest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	start := clock.Now()
+	s := NewMemoryTokenStore(WithClock(clock), WithSweepInterval(0))
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+
+	token, err := IssueToken(context.TODO(), info, s, k, WithClock(clock))
+	require.NoError(t, err)
+
+	table := []struct {
+		name     string
+		opts     []auth.Option
+		advance  time.Duration
+		lastUsed time.Time
+	}{
+		{
+			name: "it does not update last used time when tracking disabled",
+		},
+		{
+			name:     "it update last used time on first use",
+			opts:     []auth.Option{WithLastUsedInterval(time.Minute)},
+			lastUsed: start,
+		},
+		{
+			name:     "it throttle last used time updates within the interval",
+			opts:     []auth.Option{WithLastUsedInterval(time.Minute)},
+			advance:  time.Second * 30,
+			lastUsed: start,
+		},
+		{
+			name:     "it update last used time once the interval elapsed",
+			opts:     []auth.Option{WithLastUsedInterval(time.Minute)},
+			advance:  time.Second * 30,
+			lastUsed: start.Add(time.Minute),
+		},
+	}
+
+	for _, tt := range table {
+		clock.Advance(tt.advance)
+		opts := append([]auth.Option{WithClock(clock)}, tt.opts...)
+
+		_, _, err := GetAuthenticateFunc(s, k, opts...)(context.TODO(), nil, token)
+		require.NoError(t, err, tt.name)
+
+		got, err := s.Lookup(context.TODO(), tokenSignature(t, k, token))
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.lastUsed, got.LastUsedAt, tt.name)
+		require.Equal(t, start, got.CreatedAt, tt.name)
+	}
+}
+
+func TestKeyID(t *testing.T) {
+	ctx := context.TODO()
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+	ks := NewKeySet("k1", []byte("key1"))
+	s := NewMemoryTokenStore(WithSweepInterval(0))
+
+	legacy, err := IssueToken(ctx, info, s, StaticSecret("key1"))
+	require.NoError(t, err)
+
+	k1, err := IssueToken(ctx, info, s, ks)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(k1, "s.v2.k1."), k1)
+
+	tk, err := s.Lookup(ctx, tokenSignature(t, ks, k1))
+	require.NoError(t, err)
+	require.Equal(t, "k1", tk.KeyID)
+
+	ks.Rotate("k2", []byte("key2"))
+	k2, err := IssueToken(ctx, info, s, ks)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(k2, "s.v2.k2."), k2)
+
+	table := []struct {
+		name     string
+		token    string
+		opts     []auth.Option
+		keeper   SecretsKeeper
+		contains string
+	}{
+		{
+			name:  "it parse token signed by the previous key id",
+			token: k1,
+		},
+		{
+			name:  "it parse token signed by the current key id",
+			token: k2,
+		},
+		{
+			name:  "it parse legacy token using the keeper keys",
+			token: legacy,
+		},
+		{
+			name:     "it reject token signed by retired key id",
+			token:    k1,
+			opts:     []auth.Option{WithRetiredKeyIDs("k1")},
+			contains: ErrRetiredKeyID.Error(),
+		},
+		{
+			name:     "it reject token when its key id replaced",
+			token:    strings.Replace(k1, ".k1.", ".k2.", 1),
+			contains: "invalid token signature",
+		},
+		{
+			name:     "it reject token signed by unknown key id",
+			token:    strings.Replace(k1, ".k1.", ".k3.", 1),
+			contains: ErrKeyNotFound.Error(),
+		},
+		{
+			name:     "it reject token missing key id",
+			token:    "s.v2.dGVzdHRlc3R0ZXN0dGVzdHRlc3R0ZXN0dGVzdHRlc3Q",
+			contains: "token is missing key id",
+		},
+		{
+			name:     "it reject token with key id when keeper does not support key ids",
+			token:    k1,
+			keeper:   StaticSecret("key1"),
+			contains: "does not support key ids",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			k := tt.keeper
+			if k == nil {
+				k = ks
+			}
+
+			_, _, err := GetAuthenticateFunc(s, k, tt.opts...)(ctx, nil, tt.token)
+
+			if len(tt.contains) > 0 {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.contains)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	ks.Remove("k1")
+	_, _, err = GetAuthenticateFunc(s, ks)(ctx, nil, k1)
+	require.Error(t, err)
+}
+
+func tokenSignature(t *testing.T, k SecretsKeeper, token string) string {
+	sig, err := newOpaque(nil, k).verify(token)
+	require.NoError(t, err)
+	return sig
+}
+
+type ttlCache struct {
+	auth.Cache
+	ttl time.Duration
+}
+
+func (c *ttlCache) StoreWithTTL(key interface{}, value interface{}, ttl time.Duration) {
+	c.ttl = ttl
+	c.Cache.StoreWithTTL(key, value, ttl)
+}
+
 func TestParse(t *testing.T) {
 	tests := []struct {
 		k        *testSecretsKeeper
@@ -0,0 +1,303 @@
+// Package opaquetest provides utilities for testing opaque token stores.
+package opaquetest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/opaque"
+)
+
+// TestTokenStore runs the conformance tests against the token store returned by newStore,
+// each test case runs against a new empty store.
+// The opaque.UserTokenStore test cases skipped, if the store does not implement it.
+//
+// The store must return opaque.ErrTokenNotFound or an error wrapping it,
+// when the token not found, revoked, or has expired.
+// The store must preserve the token auth info name, id, groups, and extensions.
+//
+//	func TestStore(t *testing.T) {
+//		opaquetest.TestTokenStore(t, func(t *testing.T) opaque.TokenStore {
+//			return NewStore()
+//		})
+//	}
+func TestTokenStore(t *testing.T, newStore func(t *testing.T) opaque.TokenStore) {
+	table := []struct {
+		name string
+		fn   func(t *testing.T, s opaque.TokenStore)
+		user bool
+	}{
+		{
+			name: "it lookup stored token",
+			fn:   testStoreLookup,
+		},
+		{
+			name: "it return ErrTokenNotFound when token does not exist",
+			fn:   testLookupNotFound,
+		},
+		{
+			name: "it return ErrTokenNotFound when token expired",
+			fn:   testLookupExpired,
+		},
+		{
+			name: "it return ErrTokenNotFound when token revoked",
+			fn:   testRevoke,
+		},
+		{
+			name: "it return nil error when revoking unknown token",
+			fn:   testRevokeUnknown,
+		},
+		{
+			name: "it preserve token max lifespan",
+			fn:   testMaxLifespan,
+		},
+		{
+			name: "it preserve token client metadata and key id",
+			fn:   testMetadata,
+		},
+		{
+			name: "it replace token with the same signature",
+			fn:   testReplace,
+		},
+		{
+			name: "it is safe for concurrent use",
+			fn:   testConcurrent,
+		},
+		{
+			name: "it list user unexpired tokens",
+			fn:   testListByUser,
+			user: true,
+		},
+		{
+			name: "it revoke all user tokens",
+			fn:   testRevokeByUser,
+			user: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStore(t)
+			if c, ok := s.(interface{ Close() error }); ok {
+				defer c.Close()
+			}
+
+			if _, ok := s.(opaque.UserTokenStore); tt.user && !ok {
+				t.Skip("store does not implement opaque.UserTokenStore")
+			}
+
+			tt.fn(t, s)
+		})
+	}
+}
+
+// NewToken return's a token valid for an hour, mapped to a test auth info.
+func NewToken(signature string) opaque.Token {
+	ext := auth.Extensions{}
+	ext.Set("x-test", signature)
+
+	return opaque.Token{
+		Lifespan:  time.Now().Add(time.Hour).Round(time.Second),
+		Signature: signature,
+		Prefix:    "s",
+		Info:      auth.NewDefaultUser("test-"+signature, signature, []string{"group"}, ext),
+	}
+}
+
+func assertToken(t *testing.T, expected, got opaque.Token) {
+	assert.Equal(t, expected.Signature, got.Signature)
+	assert.Equal(t, expected.Prefix, got.Prefix)
+	assert.True(t, expected.Lifespan.Equal(got.Lifespan), "lifespan: %v != %v", expected.Lifespan, got.Lifespan)
+	assert.True(
+		t,
+		expected.MaxLifespan.Equal(got.MaxLifespan),
+		"max lifespan: %v != %v", expected.MaxLifespan, got.MaxLifespan,
+	)
+	assert.True(t, expected.CreatedAt.Equal(got.CreatedAt), "created at: %v != %v", expected.CreatedAt, got.CreatedAt)
+	assert.True(
+		t,
+		expected.LastUsedAt.Equal(got.LastUsedAt),
+		"last used at: %v != %v", expected.LastUsedAt, got.LastUsedAt,
+	)
+	assert.Equal(t, expected.ClientIP, got.ClientIP)
+	assert.Equal(t, expected.UserAgent, got.UserAgent)
+	assert.Equal(t, expected.Device, got.Device)
+	assert.Equal(t, expected.KeyID, got.KeyID)
+
+	if assert.NotNil(t, got.Info) {
+		assert.Equal(t, expected.Info.GetUserName(), got.Info.GetUserName())
+		assert.Equal(t, expected.Info.GetID(), got.Info.GetID())
+		assert.Equal(t, expected.Info.GetGroups(), got.Info.GetGroups())
+		assert.Equal(t, expected.Info.GetExtensions(), got.Info.GetExtensions())
+	}
+}
+
+func testStoreLookup(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	tk := NewToken("sig")
+
+	require.NoError(t, s.Store(ctx, tk))
+
+	got, err := s.Lookup(ctx, tk.Signature)
+	require.NoError(t, err)
+	assertToken(t, tk, got)
+}
+
+func testLookupNotFound(t *testing.T, s opaque.TokenStore) {
+	_, err := s.Lookup(context.Background(), "unknown")
+	assert.True(t, errors.Is(err, opaque.ErrTokenNotFound), "got %v", err)
+}
+
+func testLookupExpired(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	tk := NewToken("sig")
+	tk.Lifespan = time.Now().Add(-time.Hour)
+
+	require.NoError(t, s.Store(ctx, tk))
+
+	_, err := s.Lookup(ctx, tk.Signature)
+	assert.True(t, errors.Is(err, opaque.ErrTokenNotFound), "got %v", err)
+}
+
+func testRevoke(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	tk := NewToken("sig")
+	other := NewToken("other")
+
+	require.NoError(t, s.Store(ctx, tk))
+	require.NoError(t, s.Store(ctx, other))
+	require.NoError(t, s.Revoke(ctx, tk.Signature))
+
+	_, err := s.Lookup(ctx, tk.Signature)
+	assert.True(t, errors.Is(err, opaque.ErrTokenNotFound), "got %v", err)
+
+	_, err = s.Lookup(ctx, other.Signature)
+	assert.NoError(t, err)
+}
+
+func testRevokeUnknown(t *testing.T, s opaque.TokenStore) {
+	assert.NoError(t, s.Revoke(context.Background(), "unknown"))
+}
+
+func testMaxLifespan(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	tk := NewToken("sig")
+	tk.MaxLifespan = tk.Lifespan.Add(time.Hour)
+
+	require.NoError(t, s.Store(ctx, tk))
+
+	got, err := s.Lookup(ctx, tk.Signature)
+	require.NoError(t, err)
+	assertToken(t, tk, got)
+}
+
+func testMetadata(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	tk := NewToken("sig")
+	tk.CreatedAt = time.Now().Add(-time.Hour).Round(time.Second)
+	tk.LastUsedAt = time.Now().Round(time.Second)
+	tk.ClientIP = "192.0.2.1"
+	tk.UserAgent = "test-agent/1.0"
+	tk.Device = "test device"
+	tk.KeyID = "kid"
+
+	require.NoError(t, s.Store(ctx, tk))
+
+	got, err := s.Lookup(ctx, tk.Signature)
+	require.NoError(t, err)
+	assertToken(t, tk, got)
+}
+
+func testReplace(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	tk := NewToken("sig")
+
+	require.NoError(t, s.Store(ctx, tk))
+
+	tk.Lifespan = tk.Lifespan.Add(time.Hour)
+	require.NoError(t, s.Store(ctx, tk))
+
+	got, err := s.Lookup(ctx, tk.Signature)
+	require.NoError(t, err)
+	assertToken(t, tk, got)
+}
+
+func testConcurrent(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tk := NewToken(strconv.Itoa(i))
+			assert.NoError(t, s.Store(ctx, tk))
+			_, err := s.Lookup(ctx, tk.Signature)
+			assert.NoError(t, err)
+			assert.NoError(t, s.Revoke(ctx, tk.Signature))
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// NewUserToken return's a token valid for an hour, mapped to a test auth info with the given user id.
+func NewUserToken(signature, userID string) opaque.Token {
+	t := NewToken(signature)
+	t.Info.SetID(userID)
+	return t
+}
+
+func testListByUser(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	us := s.(opaque.UserTokenStore)
+	first := NewUserToken("first", "user")
+	second := NewUserToken("second", "user")
+	second.Lifespan = second.Lifespan.Add(time.Minute)
+	expired := NewUserToken("expired", "user")
+	expired.Lifespan = time.Now().Add(-time.Hour)
+
+	for _, tk := range []opaque.Token{second, first, expired, NewUserToken("other", "other")} {
+		require.NoError(t, s.Store(ctx, tk))
+	}
+
+	got, err := us.ListByUser(ctx, "user")
+	require.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assertToken(t, first, got[0])
+		assertToken(t, second, got[1])
+	}
+
+	got, err = us.ListByUser(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Len(t, got, 0)
+}
+
+func testRevokeByUser(t *testing.T, s opaque.TokenStore) {
+	ctx := context.Background()
+	us := s.(opaque.UserTokenStore)
+
+	for _, tk := range []opaque.Token{NewUserToken("a", "user"), NewUserToken("b", "user"), NewUserToken("c", "other")} {
+		require.NoError(t, s.Store(ctx, tk))
+	}
+
+	sigs, err := us.RevokeByUser(ctx, "user")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, sigs)
+
+	for _, sig := range []string{"a", "b"} {
+		_, err := s.Lookup(ctx, sig)
+		assert.True(t, errors.Is(err, opaque.ErrTokenNotFound), "got %v", err)
+	}
+
+	_, err = s.Lookup(ctx, "c")
+	assert.NoError(t, err)
+}
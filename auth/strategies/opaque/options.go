@@ -2,9 +2,11 @@ package opaque
 
 import (
 	"crypto"
+	"net/http"
 	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
 )
 
 // WithTokenLength is the size of tokens to generate.
@@ -40,6 +42,113 @@ func WithTokenPrefix(prefix string) auth.Option {
 	})
 }
 
+// WithIdleTimeout enables sliding expiration and sets the token idle timeout,
+// the token lifespan extends on each use by the idle timeout, up to the token absolute lifetime.
+//
+// The lifespan renewal written through the TokenStore when the token parsed,
+// and the strategy returned by New caches the tokens up to the renewal interval,
+// to renew their lifespan while in use.
+// Note that the function returned by GetAuthenticateFunc renews the lifespan only on cache misses.
+//
+// Default is 0 (disabled).
+func WithIdleTimeout(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if o, ok := v.(*opaque); ok {
+			o.idle = d
+		}
+	})
+}
+
+// WithAbsoluteLifetime sets the token absolute lifetime since issuance,
+// the token lifespan never renewed beyond it when sliding expiration enabled.
+//
+// Default is the token exp duration (see WithExpDuration).
+func WithAbsoluteLifetime(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if o, ok := v.(*opaque); ok {
+			o.absolute = d
+		}
+	})
+}
+
+// WithRenewInterval sets the minimum interval between the token lifespan renewals,
+// to throttle the TokenStore writes when sliding expiration enabled.
+//
+// Default is a tenth of the idle timeout.
+func WithRenewInterval(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if o, ok := v.(*opaque); ok {
+			o.interval = d
+		}
+	})
+}
+
+// WithRequest sets the request of the client the token issued to,
+// to record the client IP address and user agent on the issued token.
+//
+// WithRequest used only when issuing a token.
+func WithRequest(r *http.Request) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if o, ok := v.(*opaque); ok {
+			o.r = r
+		}
+	})
+}
+
+// WithClientIPHeader sets the header to read the client IP address from, e.g. X-Forwarded-For,
+// the last header value used, which is the value appended by the closest proxy.
+// It must be set only when the server runs behind a trusted proxy, as clients can forge the header.
+//
+// Default is "" (the request remote address).
+func WithClientIPHeader(header string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if o, ok := v.(*opaque); ok {
+			o.ipHeader = header
+		}
+	})
+}
+
+// WithDeviceLabel sets the label of the client device the token issued to, e.g. "Firefox on Linux".
+//
+// WithDeviceLabel used only when issuing a token.
+func WithDeviceLabel(label string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if o, ok := v.(*opaque); ok {
+			o.device = label
+		}
+	})
+}
+
+// WithLastUsedInterval enables last-used tracking and sets the minimum interval,
+// between the token last used time updates, to throttle the TokenStore writes.
+//
+// The last used time written through the TokenStore when the token parsed,
+// and the strategy returned by New caches the tokens up to the last-used interval.
+//
+// Default is 0 (disabled).
+func WithLastUsedInterval(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if o, ok := v.(*opaque); ok {
+			o.lastUsed = d
+		}
+	})
+}
+
+// WithRetiredKeyIDs sets the retired key ids,
+// tokens signed by a retired key id rejected, even if the key still held by the secrets keeper.
+//
+// Note that already cached tokens remain valid until evicted from the cache.
+func WithRetiredKeyIDs(kids ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if o, ok := v.(*opaque); ok {
+			o.retired = make(map[string]bool, len(kids))
+			for _, kid := range kids {
+				o.retired[kid] = true
+			}
+		}
+	})
+}
+
 // WithHash sets HMAC hash function.
 //
 // Default is crypto.SHA512_256.
@@ -50,3 +159,56 @@ func WithHash(h crypto.Hash) auth.Option {
 		}
 	})
 }
+
+// WithClock sets the clock used to compute token lifespan and verify its expiry,
+// to compute the cached token ttl, and to sweep expired tokens from the token stores.
+//
+// Default is auth.SystemClock.
+func WithClock(c auth.Clock) auth.Option {
+	tc := token.SetClock(c)
+	return auth.OptionFunc(func(v interface{}) {
+		switch o := v.(type) {
+		case *opaque:
+			o.clock = c
+		case *MemoryTokenStore:
+			o.clock = c
+		}
+		tc.Apply(v)
+	})
+}
+
+// WithStoreCapacity sets the maximum number of tokens held by the in-memory and file token stores,
+// once reached the token closest to expiry evicted.
+//
+// Default is 0 (unbounded).
+func WithStoreCapacity(n int) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*MemoryTokenStore); ok {
+			m.capacity = n
+		}
+	})
+}
+
+// WithSweepInterval sets the interval between sweeping expired tokens
+// from the in-memory and file token stores, zero disables the background sweeping.
+//
+// Default is 1m.
+func WithSweepInterval(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*MemoryTokenStore); ok {
+			m.interval = d
+		}
+	})
+}
+
+// WithCompactThreshold sets the number of stale records in the file token store log,
+// that triggers log compaction.
+//
+// Default is 1000.
+func WithCompactThreshold(n int) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if f, ok := v.(*FileTokenStore); ok {
+			f.threshold = n
+		}
+	})
+}
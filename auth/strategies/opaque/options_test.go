@@ -35,6 +35,48 @@ func TestOptions(t *testing.T) {
 			opt:      WithExpDuration(time.Hour),
 			value:    func(o *opaque) interface{} { return o.exp },
 		},
+		{
+			defaults: time.Duration(0),
+			expected: time.Minute,
+			opt:      WithIdleTimeout(time.Minute),
+			value:    func(o *opaque) interface{} { return o.idle },
+		},
+		{
+			defaults: time.Hour * 24,
+			expected: time.Hour,
+			opt:      WithAbsoluteLifetime(time.Hour),
+			value:    func(o *opaque) interface{} { return o.absolute },
+		},
+		{
+			defaults: time.Duration(0),
+			expected: time.Second,
+			opt:      WithRenewInterval(time.Second),
+			value:    func(o *opaque) interface{} { return o.interval },
+		},
+		{
+			defaults: time.Duration(0),
+			expected: time.Minute,
+			opt:      WithLastUsedInterval(time.Minute),
+			value:    func(o *opaque) interface{} { return o.lastUsed },
+		},
+		{
+			defaults: "",
+			expected: "laptop",
+			opt:      WithDeviceLabel("laptop"),
+			value:    func(o *opaque) interface{} { return o.device },
+		},
+		{
+			defaults: "",
+			expected: "X-Real-IP",
+			opt:      WithClientIPHeader("X-Real-IP"),
+			value:    func(o *opaque) interface{} { return o.ipHeader },
+		},
+		{
+			defaults: map[string]bool(nil),
+			expected: map[string]bool{"k1": true, "k2": true},
+			opt:      WithRetiredKeyIDs("k1", "k2"),
+			value:    func(o *opaque) interface{} { return o.retired },
+		},
 		{
 			defaults: crypto.SHA512_256,
 			expected: crypto.SHA256,
@@ -0,0 +1,184 @@
+package sqlstore
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect represents the SQL dialect of the underlying database.
+type Dialect struct {
+	name        string
+	placeholder func(i int) string
+	conflict    string
+	excluded    func(column string) string
+	migrations  []string
+	// probes count whether the migration at the same index already applied,
+	// for databases that implicitly commit DDL statements and therefore may apply it without recording it.
+	probes []string
+	// lock and unlock serialize concurrent migrations, lock must return 1 once acquired.
+	lock   string
+	unlock string
+	// returning reports whether the database supports DELETE ... RETURNING.
+	returning bool
+}
+
+// columns are the token table columns, in the order of the upsert arguments.
+var columns = []string{
+	"signature", "prefix", "user_id", "info", "expires_at", "max_expires_at",
+	"created_at", "last_used_at", "client_ip", "user_agent", "device", "key_id",
+}
+
+// Name return's the dialect name.
+func (d Dialect) Name() string {
+	return d.name
+}
+
+// query replaces the {table} and ? placeholders, with the table name and dialect placeholders.
+func (d Dialect) query(table, q string) string {
+	q = strings.Replace(q, "{table}", table, -1)
+
+	sb := new(strings.Builder)
+	n := 0
+
+	for _, r := range q {
+		if r == '?' {
+			n++
+			sb.WriteString(d.placeholder(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// upsert return's the statement that inserts a token, or updates the token with the same signature.
+func (d Dialect) upsert() string {
+	sets := make([]string, 0, len(columns)-1)
+	for _, c := range columns[1:] {
+		sets = append(sets, c+" = "+d.excluded(c))
+	}
+
+	return "INSERT INTO {table} (" + strings.Join(columns, ", ") + ") " +
+		"VALUES (?" + strings.Repeat(", ?", len(columns)-1) + ") " +
+		d.conflict + " " + strings.Join(sets, ", ")
+}
+
+func mysqlColumn(column string) string {
+	return "SELECT COUNT(*) FROM information_schema.columns " +
+		"WHERE table_schema = DATABASE() AND table_name = '{table}' AND column_name = '" + column + "'"
+}
+
+func mysqlIndex(index string) string {
+	return "SELECT COUNT(*) FROM information_schema.statistics " +
+		"WHERE table_schema = DATABASE() AND table_name = '{table}' AND index_name = '" + index + "'"
+}
+
+func question(int) string {
+	return "?"
+}
+
+func dollar(i int) string {
+	return "$" + strconv.Itoa(i)
+}
+
+// The schema migrations, each migration applied once and recorded in {table}_migrations table.
+// The time columns (expires_at, max_expires_at, created_at, last_used_at) hold unix nanoseconds,
+// to be portable across databases and drivers, and zero represents zero time.
+var (
+	// Postgres dialect.
+	Postgres = Dialect{
+		name:        "postgres",
+		placeholder: dollar,
+		conflict:    "ON CONFLICT (signature) DO UPDATE SET",
+		excluded:    func(c string) string { return "EXCLUDED." + c },
+		lock:        "SELECT 1 FROM pg_advisory_lock(hashtext('{table}_migrations'))",
+		unlock:      "SELECT pg_advisory_unlock(hashtext('{table}_migrations'))",
+		returning:   true,
+		migrations: []string{
+			"CREATE TABLE IF NOT EXISTS {table} (" +
+				"signature VARCHAR(255) NOT NULL PRIMARY KEY, " +
+				"prefix VARCHAR(64) NOT NULL, " +
+				"user_id VARCHAR(255) NOT NULL, " +
+				"info TEXT NOT NULL, " +
+				"expires_at BIGINT NOT NULL)",
+			"CREATE INDEX {table}_user_id_idx ON {table} (user_id)",
+			"CREATE INDEX {table}_expires_at_idx ON {table} (expires_at)",
+			"ALTER TABLE {table} ADD COLUMN max_expires_at BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN created_at BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN last_used_at BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN client_ip VARCHAR(64) NOT NULL DEFAULT ''",
+			"ALTER TABLE {table} ADD COLUMN user_agent TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE {table} ADD COLUMN device VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE {table} ADD COLUMN key_id VARCHAR(64) NOT NULL DEFAULT ''",
+		},
+	}
+
+	// MySQL dialect.
+	//
+	// MySQL implicitly commits DDL statements,
+	// therefore each migration probed before applied, to skip the applied but not recorded migrations.
+	MySQL = Dialect{
+		name:        "mysql",
+		placeholder: question,
+		conflict:    "ON DUPLICATE KEY UPDATE",
+		excluded:    func(c string) string { return "VALUES(" + c + ")" },
+		lock:        "SELECT GET_LOCK('{table}_migrations', 60)",
+		unlock:      "SELECT RELEASE_LOCK('{table}_migrations')",
+		migrations: []string{
+			"CREATE TABLE IF NOT EXISTS {table} (" +
+				"signature VARCHAR(255) NOT NULL PRIMARY KEY, " +
+				"prefix VARCHAR(64) NOT NULL, " +
+				"user_id VARCHAR(255) NOT NULL, " +
+				"info TEXT NOT NULL, " +
+				"expires_at BIGINT NOT NULL)",
+			"CREATE INDEX {table}_user_id_idx ON {table} (user_id)",
+			"CREATE INDEX {table}_expires_at_idx ON {table} (expires_at)",
+			"ALTER TABLE {table} ADD COLUMN max_expires_at BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN created_at BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN last_used_at BIGINT NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN client_ip VARCHAR(64) NOT NULL DEFAULT ''",
+			"ALTER TABLE {table} ADD COLUMN user_agent TEXT NOT NULL",
+			"ALTER TABLE {table} ADD COLUMN device VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE {table} ADD COLUMN key_id VARCHAR(64) NOT NULL DEFAULT ''",
+		},
+		probes: []string{
+			"",
+			mysqlIndex("{table}_user_id_idx"),
+			mysqlIndex("{table}_expires_at_idx"),
+			mysqlColumn("max_expires_at"),
+			mysqlColumn("created_at"),
+			mysqlColumn("last_used_at"),
+			mysqlColumn("client_ip"),
+			mysqlColumn("user_agent"),
+			mysqlColumn("device"),
+			mysqlColumn("key_id"),
+		},
+	}
+
+	// SQLite dialect, requires SQLite 3.35.0 or later.
+	SQLite = Dialect{
+		name:        "sqlite",
+		placeholder: question,
+		conflict:    "ON CONFLICT (signature) DO UPDATE SET",
+		excluded:    func(c string) string { return "excluded." + c },
+		returning:   true,
+		migrations: []string{
+			"CREATE TABLE IF NOT EXISTS {table} (" +
+				"signature TEXT NOT NULL PRIMARY KEY, " +
+				"prefix TEXT NOT NULL, " +
+				"user_id TEXT NOT NULL, " +
+				"info TEXT NOT NULL, " +
+				"expires_at INTEGER NOT NULL)",
+			"CREATE INDEX IF NOT EXISTS {table}_user_id_idx ON {table} (user_id)",
+			"CREATE INDEX IF NOT EXISTS {table}_expires_at_idx ON {table} (expires_at)",
+			"ALTER TABLE {table} ADD COLUMN max_expires_at INTEGER NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN last_used_at INTEGER NOT NULL DEFAULT 0",
+			"ALTER TABLE {table} ADD COLUMN client_ip TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE {table} ADD COLUMN user_agent TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE {table} ADD COLUMN device TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE {table} ADD COLUMN key_id TEXT NOT NULL DEFAULT ''",
+		},
+	}
+)
@@ -0,0 +1,228 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// fakeDriver implements database/sql/driver.Driver and interprets,
+// only the statements issued by the store against an in-memory table.
+type fakeDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeDB
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.dbs[name]; !ok {
+		d.dbs[name] = &fakeDB{rows: make(map[string][]driver.Value)}
+	}
+
+	return &fakeConn{db: d.dbs[name]}, nil
+}
+
+var (
+	fake        = &fakeDriver{dbs: make(map[string]*fakeDB)}
+	placeholder = regexp.MustCompile(`\$\d+`)
+)
+
+func init() {
+	sql.Register("fake", fake)
+}
+
+type fakeDB struct {
+	mu         sync.Mutex
+	rows       map[string][]driver.Value // signature -> columns[1:]
+	migrations []int64
+	schema     []string // the columns and indexes applied without recording the migration.
+	queries    []string
+}
+
+func (db *fakeDB) exec(query string, args []driver.Value) (int64, [][]driver.Value, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.queries = append(db.queries, query)
+	q := placeholder.ReplaceAllString(query, "?")
+
+	switch {
+	case strings.HasPrefix(q, "CREATE"), strings.HasPrefix(q, "ALTER"):
+		return 0, nil, nil
+	case strings.HasPrefix(q, "SELECT 1 FROM pg_advisory_lock"), strings.HasPrefix(q, "SELECT GET_LOCK"):
+		return 0, [][]driver.Value{{int64(1)}}, nil
+	case strings.HasPrefix(q, "SELECT pg_advisory_unlock"), strings.HasPrefix(q, "SELECT RELEASE_LOCK"):
+		return 0, [][]driver.Value{{int64(1)}}, nil
+	case strings.HasPrefix(q, "SELECT COUNT(*) FROM information_schema"):
+		n := int64(0)
+		for _, v := range db.schema {
+			if strings.Contains(q, "'"+v+"'") {
+				n++
+			}
+		}
+		return 0, [][]driver.Value{{n}}, nil
+	case strings.HasPrefix(q, "SELECT COUNT(*)") && strings.Contains(q, "_migrations WHERE version = ?"):
+		n := int64(0)
+		for _, v := range db.migrations {
+			if v == args[0].(int64) {
+				n++
+			}
+		}
+		return 0, [][]driver.Value{{n}}, nil
+	case strings.HasPrefix(q, "INSERT INTO") && strings.Contains(q, "_migrations"):
+		for _, v := range db.migrations {
+			if v == args[0].(int64) {
+				return 0, nil, errors.New("fake: duplicate migration version")
+			}
+		}
+		db.migrations = append(db.migrations, args[0].(int64))
+		return 1, nil, nil
+	case strings.HasPrefix(q, "SELECT MAX(version)"):
+		var max driver.Value
+		for _, v := range db.migrations {
+			if max == nil || v > max.(int64) {
+				max = v
+			}
+		}
+		return 0, [][]driver.Value{{max}}, nil
+	case strings.HasPrefix(q, "INSERT INTO"):
+		db.rows[args[0].(string)] = args[1:]
+		return 1, nil, nil
+	case strings.HasPrefix(q, "SELECT signature, prefix") && strings.Contains(q, "WHERE signature = ?"):
+		r, ok := db.rows[args[0].(string)]
+		if !ok || r[3].(int64) <= args[1].(int64) {
+			return 0, nil, nil
+		}
+		return 0, [][]driver.Value{append([]driver.Value{args[0], r[0]}, r[2:]...)}, nil
+	case strings.HasPrefix(q, "SELECT signature, prefix") && strings.Contains(q, "WHERE user_id = ?"):
+		rows := [][]driver.Value{}
+		for k, r := range db.rows {
+			if r[1] == args[0] && r[3].(int64) > args[1].(int64) {
+				rows = append(rows, append([]driver.Value{k, r[0]}, r[2:]...))
+			}
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][3].(int64) < rows[j][3].(int64) })
+		return 0, rows, nil
+	case strings.HasPrefix(q, "SELECT signature FROM") && strings.HasSuffix(q, "WHERE user_id = ? FOR UPDATE"):
+		rows := [][]driver.Value{}
+		for k, r := range db.rows {
+			if r[1] == args[0] {
+				rows = append(rows, []driver.Value{k})
+			}
+		}
+		return 0, rows, nil
+	case strings.HasPrefix(q, "DELETE") && strings.HasSuffix(q, "WHERE user_id = ? RETURNING signature"):
+		rows := [][]driver.Value{}
+		for k, r := range db.rows {
+			if r[1] == args[0] {
+				delete(db.rows, k)
+				rows = append(rows, []driver.Value{k})
+			}
+		}
+		return 0, rows, nil
+	case strings.HasPrefix(q, "DELETE") && strings.HasSuffix(q, "WHERE user_id = ?"):
+		n := int64(0)
+		for k, r := range db.rows {
+			if r[1] == args[0] {
+				delete(db.rows, k)
+				n++
+			}
+		}
+		return n, nil, nil
+	case strings.HasPrefix(q, "DELETE") && strings.HasSuffix(q, "WHERE signature = ?"):
+		if _, ok := db.rows[args[0].(string)]; !ok {
+			return 0, nil, nil
+		}
+		delete(db.rows, args[0].(string))
+		return 1, nil, nil
+	case strings.HasPrefix(q, "DELETE") && strings.HasSuffix(q, "WHERE expires_at <= ?"):
+		n := int64(0)
+		for k, r := range db.rows {
+			if r[3].(int64) <= args[0].(int64) {
+				delete(db.rows, k)
+				n++
+			}
+		}
+		return n, nil, nil
+	}
+
+	return 0, nil, errors.New("fake: unsupported query " + query)
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	n, _, err := s.db.exec(s.query, args)
+	return driver.RowsAffected(n), err
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	_, rows, err := s.db.exec(s.query, args)
+	return &fakeRows{rows: rows}, err
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,28 @@
+package sqlstore
+
+import (
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+// WithTable sets the tokens table name,
+// the name used as is within the SQL statements and must not come from untrusted input.
+//
+// Default is "go_guardian_tokens".
+func WithTable(name string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Store); ok {
+			s.table = name
+		}
+	})
+}
+
+// WithClock sets the clock used to filter and delete expired tokens.
+//
+// Default is auth.SystemClock.
+func WithClock(c auth.Clock) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Store); ok {
+			s.clock = c
+		}
+	})
+}
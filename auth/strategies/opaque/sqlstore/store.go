@@ -0,0 +1,393 @@
+// Package sqlstore provides opaque token store backed by database/sql.
+//
+// The store does not import any database driver,
+// the driver must be imported and the database opened by the caller.
+//
+//	db, err := sql.Open("postgres", dsn)
+//	store := sqlstore.New(db, sqlstore.Postgres)
+//	if err := store.Migrate(ctx); err != nil {
+//		...
+//	}
+//	strategy := opaque.New(cache, store, keeper)
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/opaque"
+)
+
+const (
+	migrationsTable = "CREATE TABLE IF NOT EXISTS {table}_migrations (version INTEGER NOT NULL PRIMARY KEY)"
+	selectColumns   = "signature, prefix, info, expires_at, max_expires_at, " +
+		"created_at, last_used_at, client_ip, user_agent, device, key_id"
+)
+
+// Store implements opaque.UserTokenStore and persists tokens in a SQL database.
+//
+// The tokens auth info persisted as JSON and restored using auth.NewUserInfo,
+// Therefore custom auth info types must be registered via auth.SetInfoConstructor.
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+	clock   auth.Clock
+}
+
+// Store stores a new token entry, or replaces the entry with the same signature.
+func (s *Store) Store(ctx context.Context, t opaque.Token) error {
+	info, uid := []byte("null"), ""
+
+	if t.Info != nil {
+		buf, err := json.Marshal(t.Info)
+		if err != nil {
+			return fmt.Errorf("strategies/opaque/sqlstore: %w", err)
+		}
+		info, uid = buf, t.Info.GetID()
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		s.query(s.dialect.upsert()),
+		t.Signature, t.Prefix, uid, string(info), t.Lifespan.UnixNano(), unixNano(t.MaxLifespan),
+		unixNano(t.CreatedAt), unixNano(t.LastUsedAt), t.ClientIP, t.UserAgent, t.Device, t.KeyID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("strategies/opaque/sqlstore: %w", err)
+	}
+
+	return nil
+}
+
+// Lookup return's the token entry by its signature,
+// opaque.ErrTokenNotFound returned if the token not found or has expired.
+func (s *Store) Lookup(ctx context.Context, signature string) (opaque.Token, error) {
+	row := s.db.QueryRowContext(
+		ctx,
+		s.query("SELECT "+selectColumns+" FROM {table} "+
+			"WHERE signature = ? AND expires_at > ?"),
+		signature, s.clock.Now().UnixNano(),
+	)
+
+	t, err := scanToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return opaque.Token{}, opaque.ErrTokenNotFound
+	}
+
+	if err != nil {
+		return opaque.Token{}, fmt.Errorf("strategies/opaque/sqlstore: %w", err)
+	}
+
+	return t, nil
+}
+
+// ListByUser return's the user unexpired token entries, ordered by their lifespan.
+func (s *Store) ListByUser(ctx context.Context, userID string) ([]opaque.Token, error) {
+	fail := func(err error) ([]opaque.Token, error) {
+		return nil, fmt.Errorf("strategies/opaque/sqlstore: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		s.query("SELECT "+selectColumns+" FROM {table} "+
+			"WHERE user_id = ? AND expires_at > ? ORDER BY expires_at"),
+		userID, s.clock.Now().UnixNano(),
+	)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	tokens := []opaque.Token{}
+
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return fail(err)
+		}
+		tokens = append(tokens, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fail(err)
+	}
+
+	return tokens, nil
+}
+
+// RevokeByUser deletes all the user token entries and return's their signatures.
+//
+// The returned signatures are exactly the deleted ones,
+// using DELETE ... RETURNING where supported, Otherwise, the user rows locked by SELECT ... FOR UPDATE.
+func (s *Store) RevokeByUser(ctx context.Context, userID string) ([]string, error) {
+	fail := func(err error) ([]string, error) {
+		return nil, fmt.Errorf("strategies/opaque/sqlstore: %w", err)
+	}
+
+	if s.dialect.returning {
+		rows, err := s.db.QueryContext(ctx, s.query("DELETE FROM {table} WHERE user_id = ? RETURNING signature"), userID)
+		if err != nil {
+			return fail(err)
+		}
+
+		signatures, err := scanSignatures(rows)
+		if err != nil {
+			return fail(err)
+		}
+
+		return signatures, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+
+	defer tx.Rollback() //nolint:errcheck
+
+	rows, err := tx.QueryContext(ctx, s.query("SELECT signature FROM {table} WHERE user_id = ? FOR UPDATE"), userID)
+	if err != nil {
+		return fail(err)
+	}
+
+	signatures, err := scanSignatures(rows)
+	if err != nil {
+		return fail(err)
+	}
+
+	if _, err := tx.ExecContext(ctx, s.query("DELETE FROM {table} WHERE user_id = ?"), userID); err != nil {
+		return fail(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return signatures, nil
+}
+
+// Revoke deletes the token entry by its signature.
+func (s *Store) Revoke(ctx context.Context, signature string) error {
+	_, err := s.db.ExecContext(ctx, s.query("DELETE FROM {table} WHERE signature = ?"), signature)
+	if err != nil {
+		return fmt.Errorf("strategies/opaque/sqlstore: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired deletes all expired tokens in bulk and return's the number of deleted tokens.
+// Typically called periodically to keep the table size bounded.
+func (s *Store) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(
+		ctx,
+		s.query("DELETE FROM {table} WHERE expires_at <= ?"),
+		s.clock.Now().UnixNano(),
+	)
+
+	if err != nil {
+		return 0, fmt.Errorf("strategies/opaque/sqlstore: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+// Migrate applies the pending schema migrations,
+// the applied migrations versions recorded in the token table name suffixed by _migrations.
+//
+// Concurrent migrations serialized by a database lock where supported (Postgres and MySQL),
+// Otherwise, a migration recorded concurrently by another caller skipped.
+func (s *Store) Migrate(ctx context.Context) error {
+	fail := func(err error) error {
+		return fmt.Errorf("strategies/opaque/sqlstore: Failed to migrate schema, %w", err)
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fail(err)
+	}
+
+	defer conn.Close()
+
+	if len(s.dialect.lock) > 0 {
+		var locked sql.NullInt64
+		if err := conn.QueryRowContext(ctx, s.query(s.dialect.lock)).Scan(&locked); err != nil {
+			return fail(err)
+		}
+
+		if locked.Int64 != 1 {
+			return fail(errors.New("unable to acquire the migrations lock"))
+		}
+
+		defer func() {
+			var v interface{}
+			_ = conn.QueryRowContext(context.Background(), s.query(s.dialect.unlock)).Scan(&v)
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, s.query(migrationsTable)); err != nil {
+		return fail(err)
+	}
+
+	var current sql.NullInt64
+
+	row := conn.QueryRowContext(ctx, s.query("SELECT MAX(version) FROM {table}_migrations"))
+	if err := row.Scan(&current); err != nil {
+		return fail(err)
+	}
+
+	for i := range s.dialect.migrations {
+		if int64(i+1) <= current.Int64 {
+			continue
+		}
+
+		if err := s.migrate(ctx, conn, i); err != nil {
+			return fail(err)
+		}
+	}
+
+	return nil
+}
+
+// migrate applies and records the migration at index i,
+// unless it's already recorded concurrently.
+func (s *Store) migrate(ctx context.Context, conn *sql.Conn, i int) error {
+	version := int64(i + 1)
+
+	err := s.apply(ctx, conn, i)
+	if err == nil {
+		return nil
+	}
+
+	var n int64
+
+	row := conn.QueryRowContext(ctx, s.query("SELECT COUNT(*) FROM {table}_migrations WHERE version = ?"), version)
+	if row.Scan(&n) == nil && n > 0 {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Store) apply(ctx context.Context, conn *sql.Conn, i int) error {
+	applied := int64(0)
+
+	if i < len(s.dialect.probes) && len(s.dialect.probes[i]) > 0 {
+		if err := conn.QueryRowContext(ctx, s.query(s.dialect.probes[i])).Scan(&applied); err != nil {
+			return err
+		}
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if applied == 0 {
+		if _, err := tx.ExecContext(ctx, s.query(s.dialect.migrations[i])); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, s.query("INSERT INTO {table}_migrations (version) VALUES (?)"), i+1); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanSignatures(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	signatures := []string{}
+
+	for rows.Next() {
+		var sig string
+		if err := rows.Scan(&sig); err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, sig)
+	}
+
+	return signatures, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanToken(sc scanner) (opaque.Token, error) {
+	var (
+		t                          opaque.Token
+		info                       string
+		exp, max, created, lastUse int64
+	)
+
+	err := sc.Scan(
+		&t.Signature, &t.Prefix, &info, &exp, &max,
+		&created, &lastUse, &t.ClientIP, &t.UserAgent, &t.Device, &t.KeyID,
+	)
+
+	if err != nil {
+		return opaque.Token{}, err
+	}
+
+	t.Lifespan = time.Unix(0, exp)
+	t.MaxLifespan = fromUnixNano(max)
+	t.CreatedAt = fromUnixNano(created)
+	t.LastUsedAt = fromUnixNano(lastUse)
+
+	if info != "null" {
+		t.Info = auth.NewUserInfo("", "", nil, nil)
+		if err := json.Unmarshal([]byte(info), t.Info); err != nil {
+			return opaque.Token{}, err
+		}
+	}
+
+	return t, nil
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+func (s *Store) query(q string) string {
+	return s.dialect.query(s.table, q)
+}
+
+// New return's new SQL token store,
+// Migrate must be called to create or upgrade the schema before using the store.
+func New(db *sql.DB, d Dialect, opts ...auth.Option) *Store {
+	s := &Store{
+		db:      db,
+		dialect: d,
+		table:   "go_guardian_tokens",
+		clock:   auth.SystemClock,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(s)
+	}
+
+	return s
+}
@@ -0,0 +1,199 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/opaque"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/opaque/opaquetest"
+)
+
+var dsn = 0
+
+func openDB(t *testing.T) (*sql.DB, *fakeDB) {
+	dsn++
+	name := strconv.Itoa(dsn)
+	db, err := sql.Open("fake", name)
+	require.NoError(t, err)
+	require.NoError(t, db.Ping())
+	return db, fake.dbs[name]
+}
+
+func TestStore(t *testing.T) {
+	for _, d := range []Dialect{Postgres, MySQL, SQLite} {
+		t.Run(d.Name(), func(t *testing.T) {
+			opaquetest.TestTokenStore(t, func(t *testing.T) opaque.TokenStore {
+				db, _ := openDB(t)
+				s := New(db, d)
+				require.NoError(t, s.Migrate(context.Background()))
+				return s
+			})
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	table := []struct {
+		name        string
+		dialect     Dialect
+		placeholder string
+	}{
+		{
+			name:        "it use dollar placeholders with postgres",
+			dialect:     Postgres,
+			placeholder: "$1",
+		},
+		{
+			name:        "it use question placeholders with mysql",
+			dialect:     MySQL,
+			placeholder: "?",
+		},
+		{
+			name:        "it use question placeholders with sqlite",
+			dialect:     SQLite,
+			placeholder: "?",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fdb := openDB(t)
+			s := New(db, tt.dialect, WithTable("tokens"))
+
+			// it apply migrations once.
+			require.NoError(t, s.Migrate(context.Background()))
+			require.NoError(t, s.Migrate(context.Background()))
+			assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, fdb.migrations)
+
+			creates := 0
+			for _, q := range fdb.queries {
+				if strings.HasPrefix(q, "CREATE INDEX") || strings.HasPrefix(q, "CREATE TABLE IF NOT EXISTS tokens (") {
+					creates++
+				}
+			}
+			assert.Equal(t, 3, creates)
+
+			require.NoError(t, s.Revoke(context.Background(), "sig"))
+			last := fdb.queries[len(fdb.queries)-1]
+			assert.Equal(t, "DELETE FROM tokens WHERE signature = "+tt.placeholder, last)
+		})
+	}
+}
+
+func TestMigrateMySQLUnrecorded(t *testing.T) {
+	db, fdb := openDB(t)
+	s := New(db, MySQL, WithTable("tokens"))
+
+	// the DDL implicitly committed, but the migration not recorded.
+	fdb.migrations = []int64{1, 2, 3}
+	fdb.schema = []string{"max_expires_at"}
+
+	require.NoError(t, s.Migrate(context.Background()))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, fdb.migrations)
+
+	for _, q := range fdb.queries {
+		assert.NotContains(t, q, "ADD COLUMN max_expires_at")
+	}
+}
+
+func TestMigrateConcurrent(t *testing.T) {
+	for _, d := range []Dialect{Postgres, MySQL, SQLite} {
+		t.Run(d.Name(), func(t *testing.T) {
+			db, fdb := openDB(t)
+			s := New(db, d)
+			wg := new(sync.WaitGroup)
+
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					assert.NoError(t, s.Migrate(context.Background()))
+				}()
+			}
+
+			wg.Wait()
+			assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, fdb.migrations)
+		})
+	}
+}
+
+func TestRevokeByUser(t *testing.T) {
+	table := []struct {
+		dialect Dialect
+		query   string
+	}{
+		{
+			dialect: Postgres,
+			query:   "DELETE FROM tokens WHERE user_id = $1 RETURNING signature",
+		},
+		{
+			dialect: MySQL,
+			query:   "SELECT signature FROM tokens WHERE user_id = ? FOR UPDATE",
+		},
+		{
+			dialect: SQLite,
+			query:   "DELETE FROM tokens WHERE user_id = ? RETURNING signature",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			ctx := context.Background()
+			db, fdb := openDB(t)
+			s := New(db, tt.dialect, WithTable("tokens"))
+			require.NoError(t, s.Migrate(ctx))
+			require.NoError(t, s.Store(ctx, opaquetest.NewToken("sig")))
+
+			sigs, err := s.RevokeByUser(ctx, opaquetest.NewToken("sig").Info.GetID())
+			require.NoError(t, err)
+			assert.Equal(t, []string{"sig"}, sigs)
+			assert.Contains(t, fdb.queries, tt.query)
+			assert.Len(t, fdb.rows, 0)
+		})
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	clock := authtest.NewFakeClock(time.Now())
+	db, fdb := openDB(t)
+	s := New(db, Postgres, WithClock(clock))
+	require.NoError(t, s.Migrate(ctx))
+
+	require.NoError(t, s.Store(ctx, opaquetest.NewToken("a")))
+	require.NoError(t, s.Store(ctx, opaquetest.NewToken("b")))
+
+	n, err := s.DeleteExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	clock.Advance(time.Hour * 2)
+
+	_, err = s.Lookup(ctx, "a")
+	assert.Equal(t, opaque.ErrTokenNotFound, err)
+
+	n, err = s.DeleteExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Len(t, fdb.rows, 0)
+}
+
+func TestStoreUserID(t *testing.T) {
+	ctx := context.Background()
+	db, fdb := openDB(t)
+	s := New(db, MySQL)
+	require.NoError(t, s.Migrate(ctx))
+
+	tk := opaquetest.NewToken("sig")
+	require.NoError(t, s.Store(ctx, tk))
+	assert.Equal(t, tk.Info.GetID(), fdb.rows["sig"][1])
+}
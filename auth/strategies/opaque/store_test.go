@@ -0,0 +1,136 @@
+package opaque_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/opaque"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/opaque/opaquetest"
+)
+
+func TestMemoryTokenStore(t *testing.T) {
+	opaquetest.TestTokenStore(t, func(t *testing.T) opaque.TokenStore {
+		return opaque.NewMemoryTokenStore()
+	})
+}
+
+func TestFileTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opaque")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	i := 0
+	opaquetest.TestTokenStore(t, func(t *testing.T) opaque.TokenStore {
+		i++
+		s, err := opaque.NewFileTokenStore(filepath.Join(dir, strings.Repeat("x", i)))
+		require.NoError(t, err)
+		return s
+	})
+}
+
+func TestMemoryTokenStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	clock := authtest.NewFakeClock(time.Now())
+	s := opaque.NewMemoryTokenStore(opaque.WithClock(clock), opaque.WithSweepInterval(0))
+	defer s.Close()
+
+	tk := opaquetest.NewToken("sig")
+	require.NoError(t, s.Store(ctx, tk))
+	require.NoError(t, s.Store(ctx, opaquetest.NewToken("other")))
+
+	s.Sweep()
+	assert.Equal(t, 2, s.Len())
+
+	clock.Advance(time.Hour * 2)
+	s.Sweep()
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestMemoryTokenStoreCapacity(t *testing.T) {
+	ctx := context.Background()
+	s := opaque.NewMemoryTokenStore(opaque.WithStoreCapacity(2))
+	defer s.Close()
+
+	first := opaquetest.NewToken("first")
+	first.Lifespan = first.Lifespan.Add(-time.Minute)
+
+	require.NoError(t, s.Store(ctx, first))
+	require.NoError(t, s.Store(ctx, opaquetest.NewToken("second")))
+	require.NoError(t, s.Store(ctx, opaquetest.NewToken("third")))
+
+	assert.Equal(t, 2, s.Len())
+
+	// it evict the token closest to expiry.
+	_, err := s.Lookup(ctx, "first")
+	assert.Equal(t, opaque.ErrTokenNotFound, err)
+}
+
+func TestFileTokenStorePersistence(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "opaque")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tokens.log")
+	s, err := opaque.NewFileTokenStore(path, opaque.WithCompactThreshold(3))
+	require.NoError(t, err)
+
+	tk := opaquetest.NewToken("sig")
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Store(ctx, tk))
+	}
+	require.NoError(t, s.Store(ctx, opaquetest.NewToken("revoked")))
+	require.NoError(t, s.Revoke(ctx, "revoked"))
+	require.NoError(t, s.Close())
+
+	// it compact the log once stale records reach the threshold.
+	buf, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.Count(string(buf), "\n") < 4)
+
+	// it restore tokens from the log.
+	s, err = opaque.NewFileTokenStore(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	got, err := s.Lookup(ctx, tk.Signature)
+	require.NoError(t, err)
+	assert.Equal(t, tk.Info.GetID(), got.Info.GetID())
+
+	_, err = s.Lookup(ctx, "revoked")
+	assert.Equal(t, opaque.ErrTokenNotFound, err)
+}
+
+func TestFileTokenStoreTornWrite(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "opaque")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tokens.log")
+	s, err := opaque.NewFileTokenStore(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Store(ctx, opaquetest.NewToken("sig")))
+	require.NoError(t, s.Close())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, _ = f.WriteString(`{"op":"store","Signature":"torn`)
+	_ = f.Close()
+
+	s, err = opaque.NewFileTokenStore(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.Lookup(ctx, "sig")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,65 @@
+package opaque
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+// ErrUnsupportedStore is returned by ListUserTokens and RevokeUserTokens,
+// when the token store does not implement UserTokenStore.
+var ErrUnsupportedStore = errors.New("strategies/opaque: Token store does not support user tokens")
+
+// UserTokenStore is an optional TokenStore extension,
+// to manage tokens by their user id (auth.Info.GetID),
+// e.g. to list the user active sessions, or to log out the user everywhere.
+type UserTokenStore interface {
+	TokenStore
+	// ListByUser used to get the user unexpired token entries, ordered by their lifespan.
+	ListByUser(ctx context.Context, userID string) ([]Token, error)
+	// RevokeByUser used to delete all the user token entries,
+	// and return's the deleted entries signatures.
+	RevokeByUser(ctx context.Context, userID string) ([]string, error)
+}
+
+// ListUserTokens return's the user unexpired tokens,
+// If the store does not implement UserTokenStore, ErrUnsupportedStore returned.
+func ListUserTokens(ctx context.Context, s TokenStore, userID string) ([]Token, error) {
+	us, ok := s.(UserTokenStore)
+	if !ok {
+		return nil, ErrUnsupportedStore
+	}
+	return us.ListByUser(ctx, userID)
+}
+
+// RevokeUserTokens revokes all the user tokens, and deletes them from the cache,
+// The cache must be the one passed to New.
+// If the store does not implement UserTokenStore, ErrUnsupportedStore returned.
+func RevokeUserTokens(ctx context.Context, c auth.Cache, s TokenStore, userID string) error {
+	us, ok := s.(UserTokenStore)
+	if !ok {
+		return ErrUnsupportedStore
+	}
+
+	signatures, err := us.RevokeByUser(ctx, userID)
+
+	// delete the revoked tokens, even on partial failure.
+	for _, sig := range signatures {
+		c.Delete(sig)
+	}
+
+	return err
+}
+
+// RevokeTokenSignature revokes token by its signature, and deletes it from the cache,
+// The cache must be the one passed to New.
+// Typically used to revoke a single session listed by ListUserTokens.
+func RevokeTokenSignature(ctx context.Context, c auth.Cache, s TokenStore, signature string) error {
+	if err := s.Revoke(ctx, signature); err != nil {
+		return err
+	}
+
+	c.Delete(signature)
+	return nil
+}
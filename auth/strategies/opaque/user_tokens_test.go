@@ -0,0 +1,88 @@
+package opaque
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shaj13/libcache"
+	_ "github.com/shaj13/libcache/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/token"
+)
+
+func TestRevokeUserTokens(t *testing.T) {
+	ctx := context.Background()
+	k := StaticSecret([]byte("test"))
+	s := NewMemoryTokenStore()
+	defer s.Close()
+
+	cache := libcache.LRU.New(0)
+	st := New(cache, s, k)
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+
+	authenticate := func(token string) error {
+		r, _ := http.NewRequest("", "", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		_, err := st.Authenticate(r.Context(), r)
+		return err
+	}
+
+	tokens := make([]string, 3)
+	for i := range tokens {
+		token, err := IssueToken(ctx, info, s, k)
+		require.NoError(t, err)
+		require.NoError(t, authenticate(token))
+		tokens[i] = token
+	}
+
+	got, err := ListUserTokens(ctx, s, "test_id")
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	// it revoke a single session and invalidate its cache entry.
+	require.NoError(t, RevokeTokenSignature(ctx, cache, s, got[0].Signature))
+	assert.Equal(t, 2, cache.Len())
+
+	// it revoke all user sessions and invalidate their cache entries.
+	require.NoError(t, RevokeUserTokens(ctx, cache, s, "test_id"))
+	assert.Equal(t, 0, cache.Len())
+
+	for _, token := range tokens {
+		assert.Error(t, authenticate(token))
+	}
+}
+
+func TestNewCallerHasher(t *testing.T) {
+	ctx := context.Background()
+	k := StaticSecret([]byte("test"))
+	s := NewMemoryTokenStore()
+	defer s.Close()
+
+	cache := libcache.LRU.New(0)
+	st := New(cache, s, k, token.SetHasher(strings.ToUpper))
+	info := auth.NewDefaultUser("test", "test_id", nil, nil)
+
+	tk, err := IssueToken(ctx, info, s, k)
+	require.NoError(t, err)
+
+	r, _ := http.NewRequest("", "", nil)
+	r.Header.Set("Authorization", "Bearer "+tk)
+	_, err = st.Authenticate(r.Context(), r)
+	require.NoError(t, err)
+
+	_, ok := cache.Load(strings.ToUpper(tk))
+	assert.True(t, ok)
+}
+
+func TestUnsupportedStore(t *testing.T) {
+	_, err := ListUserTokens(context.Background(), &testStore{}, "id")
+	assert.Equal(t, ErrUnsupportedStore, err)
+
+	err = RevokeUserTokens(context.Background(), libcache.LRU.New(0), &testStore{}, "id")
+	assert.Equal(t, ErrUnsupportedStore, err)
+}
@@ -25,12 +25,15 @@ func New(fn AuthenticateFunc, ac auth.Cache, opts ...auth.Option) auth.Strategy
 	c := new(cachedToken)
 	c.cache = ac
 	c.fn = fn
-	return newCore(c, opts...)
+	core := newCore(c, opts...)
+	c.clock = core.clock
+	return core
 }
 
 type cachedToken struct {
 	cache auth.Cache
 	fn    AuthenticateFunc
+	clock auth.Clock
 }
 
 func (c *cachedToken) authenticate(ctx context.Context, r *http.Request, hash, token string) (auth.Info, error) {
@@ -39,6 +42,12 @@ func (c *cachedToken) authenticate(ctx context.Context, r *http.Request, hash, t
 		if !ok {
 			return nil, auth.NewTypeError("strategies/token:", (*auth.Info)(nil), v)
 		}
+		if e, ok := c.cache.(interface {
+			Expiry(interface{}) (time.Time, bool)
+		}); ok {
+			exp, _ := e.Expiry(hash)
+			recordExpiresAt(ctx, exp)
+		}
 		return info, nil
 	}
 
@@ -48,7 +57,8 @@ func (c *cachedToken) authenticate(ctx context.Context, r *http.Request, hash, t
 		return nil, err
 	}
 
-	c.cache.StoreWithTTL(hash, info, time.Until(t))
+	recordExpiresAt(ctx, t)
+	c.cache.StoreWithTTL(hash, info, t.Sub(c.clock.Now()))
 	return info, nil
 }
 
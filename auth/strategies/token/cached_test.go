@@ -3,6 +3,7 @@ package token
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -62,6 +63,30 @@ func TestNewCahced(t *testing.T) {
 	}
 }
 
+func TestCachedHashedToken(t *testing.T) {
+	cache := libcache.LRU.New(0)
+	info := auth.NewDefaultUser("1", "1", nil, nil)
+	fn := func(_ context.Context, _ *http.Request, token string) (auth.Info, time.Time, error) {
+		// the authenticate func must receive the token itself, not its hash.
+		assert.Equal(t, "token", token)
+		return info, time.Time{}, nil
+	}
+
+	strategy := New(fn, cache, SetHasher(strings.ToUpper))
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer token")
+
+	got, err := strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, info, got)
+
+	// the cache must be keyed by the token hash.
+	_, ok := cache.Load("TOKEN")
+	assert.True(t, ok)
+	_, ok = cache.Load("token")
+	assert.False(t, ok)
+}
+
 func TestCahcedTokenAppend(t *testing.T) {
 	cache := libcache.LRU.New(0)
 	s := New(nil, cache)
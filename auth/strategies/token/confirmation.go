@@ -0,0 +1,83 @@
+package token
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+)
+
+const (
+	jktExtName = "x-go-guardian-cnf-jkt"
+	x5tExtName = "x-go-guardian-cnf-x5t#S256"
+)
+
+var (
+	// ErrMissingCertificate is returned by certificate-bound token verification,
+	// when the request connection does not carry a client certificate.
+	ErrMissingCertificate = errors.New("strategies/token: Missing client certificate")
+
+	// ErrCertificateMismatch is returned by certificate-bound token verification,
+	// when the access token is not bound to the request client certificate.
+	ErrCertificateMismatch = errors.New("strategies/token: Client certificate does not match the token confirmation")
+)
+
+// WithConfirmation add the provided token confirmation (cnf) to the provided auth.info.
+// Typically used by token strategies to bind the token to a proof-of-possession key,
+// to be verified later when DPoP or certificate-bound verification enabled.
+//
+//	token.WithConfirmation(info, claims.Confirmation{JWKThumbprint: jkt})
+func WithConfirmation(info auth.Info, cnf claims.Confirmation) {
+	if len(cnf.JWKThumbprint) == 0 && len(cnf.X509Thumbprint) == 0 {
+		return
+	}
+
+	ext := auth.Extensions{}
+
+	if v := info.GetExtensions(); v != nil {
+		ext = v
+	}
+
+	if len(cnf.JWKThumbprint) > 0 {
+		ext.Set(jktExtName, cnf.JWKThumbprint)
+	}
+
+	if len(cnf.X509Thumbprint) > 0 {
+		ext.Set(x5tExtName, cnf.X509Thumbprint)
+	}
+
+	info.SetExtensions(ext)
+}
+
+// GetConfirmation return's the token confirmation (cnf) from auth.info.
+// Typically used internally when DPoP or certificate-bound verification enabled.
+func GetConfirmation(info auth.Info) (cnf claims.Confirmation) {
+	if info.GetExtensions() == nil {
+		return
+	}
+
+	cnf.JWKThumbprint = info.GetExtensions().Get(jktExtName)
+	cnf.X509Thumbprint = info.GetExtensions().Get(x5tExtName)
+	return
+}
+
+// verifyCertificate verifies the token is bound to the request client certificate,
+// as defined in RFC 8705.
+func verifyCertificate(_ context.Context, r *http.Request, info auth.Info, _ string) error {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ErrMissingCertificate
+	}
+
+	sum := sha256.Sum256(r.TLS.PeerCertificates[0].Raw)
+	x5t := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	if !equalString(GetConfirmation(info).X509Thumbprint, x5t) {
+		return ErrCertificateMismatch
+	}
+
+	return nil
+}
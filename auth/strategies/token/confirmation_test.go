@@ -0,0 +1,98 @@
+package token
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+)
+
+func TestConfirmation(t *testing.T) {
+	info := auth.NewDefaultUser("test", "1", nil, nil)
+	assert.Equal(t, claims.Confirmation{}, GetConfirmation(info))
+
+	WithConfirmation(info, claims.Confirmation{})
+	assert.Empty(t, info.GetExtensions())
+
+	cnf := claims.Confirmation{JWKThumbprint: "jkt", X509Thumbprint: "x5t"}
+	WithConfirmation(info, cnf)
+	assert.Equal(t, cnf, GetConfirmation(info))
+}
+
+func TestCertificateBound(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("test-certificate")}
+	sum := sha256.Sum256(cert.Raw)
+	x5t := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	bound := auth.NewDefaultUser("test", "1", nil, nil)
+	WithConfirmation(bound, claims.Confirmation{X509Thumbprint: x5t})
+
+	table := []struct {
+		name     string
+		token    string
+		certs    []*x509.Certificate
+		tls      bool
+		expected error
+	}{
+		{
+			name:  "it authenticate request when certificate match token",
+			token: "bound",
+			tls:   true,
+			certs: []*x509.Certificate{cert},
+		},
+		{
+			name:     "it return error when connection not tls",
+			token:    "bound",
+			expected: ErrMissingCertificate,
+		},
+		{
+			name:     "it return error when connection has no client certificate",
+			token:    "bound",
+			tls:      true,
+			expected: ErrMissingCertificate,
+		},
+		{
+			name:     "it return error when certificate does not match token",
+			token:    "bound",
+			tls:      true,
+			certs:    []*x509.Certificate{{Raw: []byte("other")}},
+			expected: ErrCertificateMismatch,
+		},
+		{
+			name:     "it return error when token not bound",
+			token:    "unbound",
+			tls:      true,
+			certs:    []*x509.Certificate{cert},
+			expected: ErrCertificateMismatch,
+		},
+	}
+
+	strategy := NewStatic(
+		map[string]auth.Info{
+			"bound":   bound,
+			"unbound": auth.NewDefaultUser("test", "2", nil, nil),
+		},
+		SetCertificateBound(),
+	)
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{PeerCertificates: tt.certs}
+			}
+
+			info, err := strategy.Authenticate(r.Context(), r)
+			assert.Equal(t, tt.expected, err)
+			assert.Equal(t, tt.expected == nil, info != nil)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/go-jose/go-jose.v2"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+)
+
+const (
+	// DPoP Authentication token type or scheme as defined in RFC 9449.
+	DPoP Type = "DPoP"
+
+	dpopHeader    = "DPoP"
+	dpopProofType = "dpop+jwt"
+)
+
+var (
+	// ErrMissingDPoPProof is returned by DPoP verification,
+	// when the request does not carry exactly one DPoP proof header.
+	ErrMissingDPoPProof = errors.New("strategies/token: Missing DPoP proof")
+
+	// ErrInvalidDPoPProof is returned by DPoP verification,
+	// when the DPoP proof is malformed or does not match the request.
+	ErrInvalidDPoPProof = errors.New("strategies/token: Invalid DPoP proof")
+
+	// ErrDPoPProofReplayed is returned by DPoP verification,
+	// when the DPoP proof jti has been already used.
+	ErrDPoPProofReplayed = errors.New("strategies/token: DPoP proof has been replayed")
+
+	// ErrDPoPKeyMismatch is returned by DPoP verification,
+	// when the access token is not bound to the DPoP proof key.
+	ErrDPoPKeyMismatch = errors.New("strategies/token: DPoP proof key does not match the token confirmation")
+)
+
+// DPoPNonceError is returned by DPoP verification when the server-provided nonce
+// is missing or invalid, Nonce holds a fresh nonce that must be sent to the client
+// within the DPoP-Nonce response header alongside the use_dpop_nonce error.
+type DPoPNonceError struct {
+	Nonce string
+}
+
+func (e DPoPNonceError) Error() string {
+	return "strategies/token: use_dpop_nonce, Authorization server requires nonce in DPoP proof"
+}
+
+// DPoPNonceKeeper generates and validates the server-provided DPoP nonces.
+type DPoPNonceKeeper interface {
+	// Nonce return's a fresh nonce.
+	Nonce() (string, error)
+	// Verify reports whether the nonce is still valid.
+	Verify(nonce string) bool
+}
+
+type dpopClaims struct {
+	ID         string       `json:"jti"`
+	Method     string       `json:"htm"`
+	URI        string       `json:"htu"`
+	IssuedAt   *claims.Time `json:"iat"`
+	TokenHash  string       `json:"ath"`
+	Nonce      string       `json:"nonce"`
+	Thumbprint string       `json:"-"`
+}
+
+type dpop struct {
+	mu      *sync.Mutex
+	enabled bool
+	cache   auth.Cache
+	nonce   DPoPNonceKeeper
+	window  time.Duration
+	leeway  time.Duration
+	clock   auth.Clock
+	uri     func(r *http.Request) string
+	algs    map[jose.SignatureAlgorithm]struct{}
+}
+
+func (d *dpop) verify(ctx context.Context, r *http.Request, info auth.Info, token string) error {
+	fail := func(format string, a ...interface{}) error {
+		return fmt.Errorf("%w, "+format, append([]interface{}{ErrInvalidDPoPProof}, a...)...)
+	}
+
+	proofs := r.Header[textproto.CanonicalMIMEHeaderKey(dpopHeader)]
+	if len(proofs) != 1 || len(proofs[0]) == 0 {
+		return ErrMissingDPoPProof
+	}
+
+	c, err := d.parse(proofs[0])
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	if c.Method != r.Method {
+		return fail("htm claim does not match request method")
+	}
+
+	if !equalURI(c.URI, d.uri(r)) {
+		return fail("htu claim does not match request uri")
+	}
+
+	if c.IssuedAt == nil || len(c.ID) == 0 {
+		return fail("proof missing iat or jti claim")
+	}
+
+	now := d.clock.Now()
+	iat := time.Time(*c.IssuedAt)
+
+	if iat.After(now.Add(d.leeway)) || iat.Before(now.Add(-d.window)) {
+		return fail("iat claim is outside the acceptable window")
+	}
+
+	ath := sha256.Sum256([]byte(token))
+	if !equalString(c.TokenHash, base64.RawURLEncoding.EncodeToString(ath[:])) {
+		return fail("ath claim does not match access token hash")
+	}
+
+	if !equalString(c.Thumbprint, GetConfirmation(info).JWKThumbprint) {
+		return ErrDPoPKeyMismatch
+	}
+
+	if d.nonce != nil && (len(c.Nonce) == 0 || !d.nonce.Verify(c.Nonce)) {
+		nonce, err := d.nonce.Nonce()
+		if err != nil {
+			return err
+		}
+		return DPoPNonceError{Nonce: nonce}
+	}
+
+	// check and record the jti atomically, to not accept concurrent replays.
+	key := c.Thumbprint + "." + c.ID
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.cache.Load(key); ok {
+		return ErrDPoPProofReplayed
+	}
+
+	d.cache.StoreWithTTL(key, struct{}{}, d.window+d.leeway)
+
+	return nil
+}
+
+func (d *dpop) parse(proof string) (*dpopClaims, error) {
+	jws, err := jose.ParseSigned(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jws.Signatures) != 1 {
+		return nil, errors.New("proof must have exactly one signature")
+	}
+
+	h := jws.Signatures[0].Protected
+
+	if typ, _ := h.ExtraHeaders[jose.HeaderType].(string); typ != dpopProofType {
+		return nil, errors.New("proof typ header must be " + dpopProofType)
+	}
+
+	if _, ok := d.algs[jose.SignatureAlgorithm(h.Algorithm)]; !ok {
+		return nil, errors.New("proof signed using unsupported algorithm " + h.Algorithm)
+	}
+
+	if h.JSONWebKey == nil || !h.JSONWebKey.Valid() || !h.JSONWebKey.IsPublic() {
+		return nil, errors.New("proof jwk header must be a valid public key")
+	}
+
+	payload, err := jws.Verify(h.JSONWebKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c := new(dpopClaims)
+	if err := json.Unmarshal(payload, c); err != nil {
+		return nil, err
+	}
+
+	tp, err := h.JSONWebKey.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Thumbprint = base64.RawURLEncoding.EncodeToString(tp)
+
+	return c, nil
+}
+
+func newDPoP() *dpop {
+	return &dpop{
+		mu:     new(sync.Mutex),
+		window: time.Minute * 5,
+		leeway: claims.DefaultLeeway,
+		clock:  auth.SystemClock,
+		uri:    requestURI,
+		algs: map[jose.SignatureAlgorithm]struct{}{
+			jose.RS256: {}, jose.RS384: {}, jose.RS512: {},
+			jose.PS256: {}, jose.PS384: {}, jose.PS512: {},
+			jose.ES256: {}, jose.ES384: {}, jose.ES512: {},
+			jose.EdDSA: {},
+		},
+	}
+}
+
+// requestURI return's the request target uri without query and fragment.
+func requestURI(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	host := r.Host
+	if len(host) == 0 {
+		host = r.URL.Host
+	}
+
+	return scheme + "://" + host + r.URL.EscapedPath()
+}
+
+// equalURI reports whether a and b refer to the same uri,
+// ignoring query and fragment components.
+func equalURI(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(ua.Scheme, ub.Scheme) &&
+		strings.EqualFold(ua.Host, ub.Host) &&
+		ua.EscapedPath() == ub.EscapedPath()
+}
+
+func equalString(a, b string) bool {
+	return len(a) > 0 && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
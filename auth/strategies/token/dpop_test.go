@@ -0,0 +1,243 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shaj13/libcache"
+	_ "github.com/shaj13/libcache/lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/go-jose/go-jose.v2"
+	"gopkg.in/go-jose/go-jose.v2/jwt"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/claims"
+)
+
+func TestDPoP(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk := jose.JSONWebKey{Key: key.Public()}
+	tp, err := jwk.Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+
+	info := auth.NewDefaultUser("test", "1", nil, nil)
+	WithConfirmation(info, claims.Confirmation{JWKThumbprint: base64.RawURLEncoding.EncodeToString(tp)})
+
+	table := []struct {
+		name     string
+		token    string
+		proof    func() []string
+		nonce    DPoPNonceKeeper
+		target   error
+		nonceErr bool
+	}{
+		{
+			name:  "it authenticate request with valid proof",
+			token: "bound",
+			proof: func() []string { return []string{newProof(t, key, "GET", "http://example.com/resource", "bound", "")} },
+		},
+		{
+			name:   "it return error when proof missing",
+			token:  "bound",
+			proof:  func() []string { return nil },
+			target: ErrMissingDPoPProof,
+		},
+		{
+			name:  "it return error when multiple proofs provided",
+			token: "bound",
+			proof: func() []string {
+				p := newProof(t, key, "GET", "http://example.com/resource", "bound", "")
+				return []string{p, p}
+			},
+			target: ErrMissingDPoPProof,
+		},
+		{
+			name:   "it return error when htm mismatch",
+			token:  "bound",
+			proof:  func() []string { return []string{newProof(t, key, "POST", "http://example.com/resource", "bound", "")} },
+			target: ErrInvalidDPoPProof,
+		},
+		{
+			name:   "it return error when htu mismatch",
+			token:  "bound",
+			proof:  func() []string { return []string{newProof(t, key, "GET", "http://example.com/other", "bound", "")} },
+			target: ErrInvalidDPoPProof,
+		},
+		{
+			name:   "it return error when ath mismatch",
+			token:  "bound",
+			proof:  func() []string { return []string{newProof(t, key, "GET", "http://example.com/resource", "x", "")} },
+			target: ErrInvalidDPoPProof,
+		},
+		{
+			name:  "it return error when proof key does not match token",
+			token: "bound",
+			proof: func() []string {
+				return []string{newProof(t, other, "GET", "http://example.com/resource", "bound", "")}
+			},
+			target: ErrDPoPKeyMismatch,
+		},
+		{
+			name:  "it return error when token not bound",
+			token: "unbound",
+			proof: func() []string {
+				return []string{newProof(t, key, "GET", "http://example.com/resource", "unbound", "")}
+			},
+			target: ErrDPoPKeyMismatch,
+		},
+		{
+			name:     "it return nonce error when nonce missing",
+			token:    "bound",
+			nonce:    testNonce("n"),
+			proof:    func() []string { return []string{newProof(t, key, "GET", "http://example.com/resource", "bound", "")} },
+			nonceErr: true,
+		},
+		{
+			name:  "it authenticate request with valid nonce",
+			token: "bound",
+			nonce: testNonce("n"),
+			proof: func() []string { return []string{newProof(t, key, "GET", "http://example.com/resource", "bound", "n")} },
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := NewStatic(
+				map[string]auth.Info{
+					"bound":   info,
+					"unbound": auth.NewDefaultUser("test", "2", nil, nil),
+				},
+				SetDPoP(libcache.LRU.New(0)),
+				SetDPoPNonce(tt.nonce),
+			)
+
+			r, _ := http.NewRequest("GET", "http://example.com/resource?q=1", nil)
+			r.Header.Set("Authorization", "DPoP "+tt.token)
+			for _, p := range tt.proof() {
+				r.Header.Add("DPoP", p)
+			}
+
+			got, err := strategy.Authenticate(r.Context(), r)
+
+			if tt.nonceErr {
+				nerr := DPoPNonceError{}
+				assert.True(t, errors.As(err, &nerr))
+				assert.Equal(t, "n", nerr.Nonce)
+				return
+			}
+
+			assert.True(t, errors.Is(err, tt.target), "got %v", err)
+			assert.Equal(t, tt.target == nil, got != nil)
+		})
+	}
+}
+
+func TestDPoPReplay(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk := jose.JSONWebKey{Key: key.Public()}
+	tp, err := jwk.Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+
+	info := auth.NewDefaultUser("test", "1", nil, nil)
+	WithConfirmation(info, claims.Confirmation{JWKThumbprint: base64.RawURLEncoding.EncodeToString(tp)})
+
+	strategy := NewStatic(map[string]auth.Info{"bound": info}, SetDPoP(libcache.LRU.New(0)))
+	proof := newProof(t, key, "GET", "http://example.com/resource", "bound", "")
+
+	r, _ := http.NewRequest("GET", "http://example.com/resource", nil)
+	r.Header.Set("Authorization", "DPoP bound")
+	r.Header.Set("DPoP", proof)
+
+	_, err = strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	_, err = strategy.Authenticate(r.Context(), r)
+	assert.Equal(t, ErrDPoPProofReplayed, err)
+}
+
+func TestDPoPConcurrentReplay(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk := jose.JSONWebKey{Key: key.Public()}
+	tp, err := jwk.Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+
+	info := auth.NewDefaultUser("test", "1", nil, nil)
+	WithConfirmation(info, claims.Confirmation{JWKThumbprint: base64.RawURLEncoding.EncodeToString(tp)})
+
+	strategy := NewStatic(map[string]auth.Info{"bound": info}, SetDPoP(libcache.LRU.New(0)))
+	proof := newProof(t, key, "GET", "http://example.com/resource", "bound", "")
+
+	var accepted int32
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, _ := http.NewRequest("GET", "http://example.com/resource", nil)
+			r.Header.Set("Authorization", "DPoP bound")
+			r.Header.Set("DPoP", proof)
+
+			if _, err := strategy.Authenticate(r.Context(), r); err == nil {
+				atomic.AddInt32(&accepted, 1)
+			} else {
+				assert.Equal(t, ErrDPoPProofReplayed, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&accepted))
+}
+
+func newProof(tb testing.TB, key *ecdsa.PrivateKey, htm, htu, token, nonce string) string {
+	opts := (&jose.SignerOptions{EmbedJWK: true}).WithType(dpopProofType)
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, opts)
+	require.NoError(tb, err)
+
+	ath := sha256.Sum256([]byte(token))
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	c := map[string]interface{}{
+		"jti":   base64.RawURLEncoding.EncodeToString(id),
+		"htm":   htm,
+		"htu":   htu,
+		"iat":   time.Now().Unix(),
+		"ath":   base64.RawURLEncoding.EncodeToString(ath[:]),
+		"nonce": nonce,
+	}
+
+	str, err := jwt.Signed(sig).Claims(c).CompactSerialize()
+	require.NoError(tb, err)
+	return str
+}
+
+type testNonce string
+
+func (n testNonce) Nonce() (string, error) {
+	return string(n), nil
+}
+
+func (n testNonce) Verify(nonce string) bool {
+	return nonce == string(n)
+}
@@ -0,0 +1,22 @@
+package token
+
+import (
+	"context"
+	"time"
+)
+
+type expiresAtKey struct{}
+
+// ContextWithExpiresAt return's a copy of ctx,
+// that token strategies use to record the authenticated token expiry time into exp,
+// without mutating the shared auth.Info.
+// Typically used by the introspection endpoint handler.
+func ContextWithExpiresAt(ctx context.Context, exp *time.Time) context.Context {
+	return context.WithValue(ctx, expiresAtKey{}, exp)
+}
+
+func recordExpiresAt(ctx context.Context, exp time.Time) {
+	if v, ok := ctx.Value(expiresAtKey{}).(*time.Time); ok {
+		*v = exp
+	}
+}
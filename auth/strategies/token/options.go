@@ -2,6 +2,8 @@ package token
 
 import (
 	"crypto"
+	"net/http"
+	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/internal"
@@ -25,6 +27,18 @@ func SetParser(p Parser) auth.Option {
 	})
 }
 
+// SetClock sets the clock used to verify token expiry, static tokens validity window,
+// and DPoP proof issuance time.
+//
+// Default auth.SystemClock.
+func SetClock(c auth.Clock) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*core); ok {
+			v.clock = c
+		}
+	})
+}
+
 // SetScopes sets the scopes to be used when verifying user access token.
 func SetScopes(scopes ...Scope) auth.Option {
 	return auth.OptionFunc(func(v interface{}) {
@@ -44,3 +58,78 @@ func SetHash(h crypto.Hash, key []byte) auth.Option {
 		}
 	})
 }
+
+// SetHasher sets the function that derives the cache key from the token, and overrides SetHash.
+// Typically used by token strategies to derive the key from an identifier embedded in the token,
+// to invalidate the cached token without knowing the token itself.
+func SetHasher(fn func(token string) string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*core); ok {
+			v.hasher = hasherFunc(fn)
+		}
+	})
+}
+
+// SetCertificateBound enables certificate-bound access tokens verification as defined in RFC 8705,
+// the token confirmation x5t#S256 must match the SHA-256 thumbprint of the request client certificate.
+// Tokens are rejected when the request connection does not carry a client certificate.
+//
+// Certificate-bound verification requires the authenticated token to carry a confirmation,
+// see WithConfirmation.
+func SetCertificateBound() auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*core); ok {
+			v.bound = true
+		}
+	})
+}
+
+// SetDPoP enables DPoP proof-of-possession verification as defined in RFC 9449,
+// and sets the strategy parser to extract token from Authorization header using DPoP scheme.
+// The cache used to detect replayed proofs by their jti.
+//
+// DPoP verification requires the authenticated token to be bound to the proof key,
+// see WithConfirmation.
+func SetDPoP(c auth.Cache) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*core); ok {
+			v.dpop.enabled = true
+			v.dpop.cache = c
+			v.parser = AuthorizationParser(string(DPoP))
+		}
+	})
+}
+
+// SetDPoPNonce sets the DPoP nonce keeper,
+// to require a server-provided nonce within the DPoP proof.
+func SetDPoPNonce(n DPoPNonceKeeper) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*core); ok {
+			v.dpop.nonce = n
+		}
+	})
+}
+
+// SetDPoPProofWindow sets the duration DPoP proof considered valid since its issuance.
+//
+// Default is 5 min.
+func SetDPoPProofWindow(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*core); ok {
+			v.dpop.window = d
+		}
+	})
+}
+
+// SetDPoPURI sets the function that return's the request target uri,
+// to be compared against the DPoP proof htu claim.
+// Typically used when the server running behind a reverse proxy.
+//
+// Default uri derived from request tls state, host, and path.
+func SetDPoPURI(fn func(r *http.Request) string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*core); ok {
+			v.dpop.uri = fn
+		}
+	})
+}
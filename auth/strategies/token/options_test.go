@@ -2,9 +2,13 @@ package token
 
 import (
 	"crypto"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/v2/auth"
 )
 
 func TestSetParser(t *testing.T) {
@@ -15,6 +19,21 @@ func TestSetParser(t *testing.T) {
 	assert.True(t, c.parser != nil)
 }
 
+func TestSetClock(t *testing.T) {
+	c := new(core)
+	clock := auth.ClockFunc(time.Now)
+	opt := SetClock(clock)
+	opt.Apply(c)
+	assert.NotNil(t, c.clock)
+}
+
+func TestSetHasher(t *testing.T) {
+	c := new(core)
+	opt := SetHasher(strings.ToUpper)
+	opt.Apply(c)
+	assert.Equal(t, "TOKEN", c.hasher.Hash("token"))
+}
+
 func TestSetScopes(t *testing.T) {
 	c := new(core)
 	opt := SetScopes(NewScope("admin", "", ""))
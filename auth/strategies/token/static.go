@@ -7,17 +7,52 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/shaj13/go-guardian/v2/auth"
 )
 
+// StaticToken represents a predefined token entry,
+// and describes the token validity window and metadata.
+type StaticToken struct {
+	// Info represent auth info token is mapped to it.
+	Info auth.Info
+	// NotBefore represent when the token starts to be valid,
+	// a zero value indicates the token is valid immediately.
+	NotBefore time.Time
+	// ExpiresAt represent when the token expires,
+	// a zero value indicates the token never expires.
+	ExpiresAt time.Time
+	// Scopes represent the token named scopes,
+	// verified by the strategy when SetScopes option provided.
+	Scopes []string
+}
+
+func (t StaticToken) verify(now time.Time) error {
+	if !t.NotBefore.IsZero() && now.Before(t.NotBefore) {
+		return ErrTokenNotYetValid
+	}
+
+	if !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt) {
+		return ErrTokenExpired
+	}
+
+	return nil
+}
+
 // NewStaticFromFile returns static auth.Strategy, populated from a CSV file.
 // The CSV file must contain records in one of following formats
 // basic record: `token,username,userid`
 // intermediate record: `token,username,userid,"group1,group2"`
 // full record: `token,username,userid,"group1,group2","extension=1,example=2"`
+// scoped record: `token,username,userid,"group1,group2","extension=1,example=2","scope1,scope2"`
+// windowed record: `token,username,userid,"group1,group2","extension=1,example=2","scope1,scope2",nbf,exp`
+//
+// Where nbf and exp represent the token not-before and expires-at times formatted as RFC 3339,
+// an empty column leaves the corresponding value unset.
 func NewStaticFromFile(path string, opts ...auth.Option) (auth.Strategy, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -25,7 +60,7 @@ func NewStaticFromFile(path string, opts ...auth.Option) (auth.Strategy, error)
 	}
 	defer file.Close()
 
-	tokens := make(map[string]auth.Info)
+	tokens := make(map[string]StaticToken)
 	reader := csv.NewReader(file)
 	reader.FieldsPerRecord = -1
 
@@ -59,6 +94,7 @@ func NewStaticFromFile(path string, opts ...auth.Option) (auth.Strategy, error)
 		}
 
 		info := auth.NewUserInfo(record[1], record[2], nil, nil)
+		t := StaticToken{Info: info}
 
 		if len(record) >= 4 {
 			info.SetGroups(strings.Split(record[3], ","))
@@ -77,54 +113,120 @@ func NewStaticFromFile(path string, opts ...auth.Option) (auth.Strategy, error)
 			info.SetExtensions(exts)
 		}
 
-		tokens[record[0]] = info
+		if len(record) >= 6 && len(record[5]) > 0 {
+			t.Scopes = strings.Split(record[5], ",")
+		}
+
+		if len(record) >= 7 {
+			if t.NotBefore, err = parseRecordTime(record[6]); err != nil {
+				return nil, fmt.Errorf("strategies/token: invalid not before time %w, Record: %v", err, record)
+			}
+		}
+
+		if len(record) >= 8 {
+			if t.ExpiresAt, err = parseRecordTime(record[7]); err != nil {
+				return nil, fmt.Errorf("strategies/token: invalid expires at time %w, Record: %v", err, record)
+			}
+		}
+
+		tokens[record[0]] = t
 	}
 
-	return NewStatic(tokens, opts...), nil
+	return NewStaticTokens(tokens, opts...), nil
 }
 
 // NewStatic returns static auth.Strategy, populated from a map.
 func NewStatic(tokens map[string]auth.Info, opts ...auth.Option) auth.Strategy {
+	m := make(map[string]StaticToken, len(tokens))
+	for k, v := range tokens {
+		m[k] = StaticToken{Info: v}
+	}
+	return NewStaticTokens(m, opts...)
+}
+
+// NewStaticTokens returns static auth.Strategy, populated from a map,
+// where each token can define its own validity window and named scopes.
+func NewStaticTokens(tokens map[string]StaticToken, opts ...auth.Option) auth.Strategy {
 	s := &static{
-		tokens: make(map[string]auth.Info, len(tokens)),
+		tokens: make(map[string]StaticToken, len(tokens)),
 		mu:     new(sync.Mutex),
 	}
 
 	c := newCore(s, opts...)
+	s.clock = c.clock
 
 	for k, v := range tokens {
-		_ = c.Append(k, v)
+		// clone the info only to add the scopes, to not mutate the caller info extensions,
+		// Otherwise, the caller info kept as is.
+		if len(v.Scopes) > 0 {
+			v.Info = cloneInfo(v.Info)
+			WithNamedScopes(v.Info, v.Scopes...)
+		}
+		s.tokens[c.hasher.Hash(k)] = v
 	}
 
 	return c
 }
 
+// ExpiringTokens returns the static tokens that expire within the provided duration,
+// including already expired tokens, sorted by their expiry time.
+// Typically used to rotate predefined tokens on schedule.
+//
+// if passed strategy is not a static token strategy, auth.ErrInvalidStrategy returned.
+func ExpiringTokens(s auth.Strategy, within time.Duration) ([]StaticToken, error) {
+	c, ok := s.(*core)
+	if !ok {
+		return nil, auth.ErrInvalidStrategy
+	}
+
+	st, ok := c.strategy.(*static)
+	if !ok {
+		return nil, auth.ErrInvalidStrategy
+	}
+
+	return st.expiring(c.clock.Now().Add(within)), nil
+}
+
+func parseRecordTime(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if len(v) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
 // Static implements strategy and define a synchronized map honor all predefined bearer tokens.
 type static struct {
 	mu     *sync.Mutex
-	tokens map[string]auth.Info
+	tokens map[string]StaticToken
+	clock  auth.Clock
 }
 
 // authenticate user request against predefined tokens by verifying request token existence in the static Map.
-// Once token found auth.Info returned with a nil error,
-// Otherwise, a nil auth.Info and ErrTokenNotFound returned.
+// Once token found and within its validity window auth.Info returned with a nil error,
+// Otherwise, a nil auth.Info and ErrTokenNotFound, ErrTokenExpired or ErrTokenNotYetValid returned.
 func (s *static) authenticate(ctx context.Context, r *http.Request, hash, _ string) (auth.Info, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	info, ok := s.tokens[hash]
+	t, ok := s.tokens[hash]
 
 	if !ok {
 		return nil, ErrTokenNotFound
 	}
 
-	return info, nil
+	if err := t.verify(s.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	recordExpiresAt(ctx, t.ExpiresAt)
+	return t.Info, nil
 }
 
 // Append add new token to static store.
 func (s *static) append(token string, info auth.Info) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.tokens[token] = info
+	s.tokens[token] = StaticToken{Info: info}
 	return nil
 }
 
@@ -135,3 +237,26 @@ func (s *static) revoke(token string) error {
 	delete(s.tokens, token)
 	return nil
 }
+
+func (s *static) expiring(deadline time.Time) []StaticToken {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := []StaticToken{}
+	for _, t := range s.tokens {
+		if !t.ExpiresAt.IsZero() && t.ExpiresAt.Before(deadline) {
+			tokens = append(tokens, t)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].ExpiresAt.Before(tokens[j].ExpiresAt)
+	})
+
+	return tokens
+}
+
+func cloneInfo(info auth.Info) auth.Info {
+	groups := append([]string(nil), info.GetGroups()...)
+	return auth.NewUserInfo(info.GetUserName(), info.GetID(), groups, info.GetExtensions().Clone())
+}
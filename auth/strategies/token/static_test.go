@@ -2,12 +2,15 @@ package token
 
 import (
 	"context"
+	"crypto"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
 )
 
 func TestNewStaticFromFile(t *testing.T) {
@@ -36,6 +39,12 @@ func TestNewStaticFromFile(t *testing.T) {
 			contains:    "3 columns (token, username, id)",
 			expectedErr: true,
 		},
+		{
+			name:        "it return error when token time invalid",
+			file:        "invalid_window",
+			contains:    "invalid not before time",
+			expectedErr: true,
+		},
 		{
 			name: "it parse file and create static tokens when file valid",
 			file: "valid",
@@ -115,6 +124,135 @@ func TestNewStatic(t *testing.T) {
 	}
 }
 
+func TestNewStaticTokensInfo(t *testing.T) {
+	info := auth.NewDefaultUser("a", "1", nil, nil)
+	tokens := map[string]StaticToken{
+		"a": {Info: info, Scopes: []string{"read"}, ExpiresAt: time.Now().Add(time.Hour)},
+	}
+
+	strategy := NewStaticTokens(tokens)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer a")
+	got, err := strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read"}, GetNamedScopes(got))
+	assert.Empty(t, info.GetExtensions())
+}
+
+type customInfo struct {
+	*auth.DefaultUser
+}
+
+func TestNewStaticCustomInfo(t *testing.T) {
+	info := customInfo{auth.NewDefaultUser("a", "1", nil, nil)}
+	strategy := NewStatic(map[string]auth.Info{"a": info})
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer a")
+	got, err := strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.IsType(t, customInfo{}, got)
+	assert.Equal(t, info, got)
+}
+
+func TestStaticTokenWindow(t *testing.T) {
+	table := []struct {
+		name     string
+		token    string
+		path     string
+		expected error
+	}{
+		{
+			name:  "it authenticate token within its validity window",
+			token: "activeToken",
+			path:  "/repo",
+		},
+		{
+			name:     "it return error when token expired",
+			token:    "expiredToken",
+			expected: ErrTokenExpired,
+		},
+		{
+			name:     "it return error when token not yet valid",
+			token:    "futureToken",
+			expected: ErrTokenNotYetValid,
+		},
+		{
+			name:     "it return error when token scopes does not match request",
+			token:    "activeToken",
+			path:     "/admin",
+			expected: ErrTokenScopes,
+		},
+	}
+
+	opt := SetScopes(NewScope("read:repo", "/repo", "GET"))
+	strategy, err := NewStaticFromFile("testdata/valid_window.csv", opt)
+	assert.NoError(t, err)
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", tt.path, nil)
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+			info, err := strategy.Authenticate(r.Context(), r)
+			assert.Equal(t, tt.expected, err)
+			assert.Equal(t, tt.expected == nil, info != nil)
+		})
+	}
+}
+
+func TestExpiringTokens(t *testing.T) {
+	now := time.Now()
+	tokens := map[string]StaticToken{
+		"a": {Info: auth.NewDefaultUser("a", "1", nil, nil), ExpiresAt: now.Add(time.Hour)},
+		"b": {Info: auth.NewDefaultUser("b", "2", nil, nil), ExpiresAt: now.Add(-time.Hour)},
+		"c": {Info: auth.NewDefaultUser("c", "3", nil, nil), ExpiresAt: now.Add(time.Hour * 48)},
+		"d": {Info: auth.NewDefaultUser("d", "4", nil, nil)},
+	}
+
+	strategy := NewStaticTokens(tokens, SetHash(crypto.SHA256, []byte("key")))
+
+	got, err := ExpiringTokens(strategy, time.Hour*24)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "2", got[0].Info.GetID())
+		assert.Equal(t, "1", got[1].Info.GetID())
+	}
+
+	_, err = ExpiringTokens(New(NoOpAuthenticate, nil), time.Hour)
+	assert.Equal(t, auth.ErrInvalidStrategy, err)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer a")
+	info, err := strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.GetID())
+}
+
+func TestStaticTokenClock(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := authtest.NewFakeClock(now)
+	tokens := map[string]StaticToken{
+		"a": {Info: auth.NewDefaultUser("a", "1", nil, nil), ExpiresAt: now.Add(time.Hour)},
+	}
+
+	strategy := NewStaticTokens(tokens, SetClock(clock))
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer a")
+
+	_, err := strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	got, err := ExpiringTokens(strategy, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, got, 0)
+
+	clock.Advance(time.Hour * 2)
+
+	_, err = strategy.Authenticate(r.Context(), r)
+	assert.Equal(t, ErrTokenExpired, err)
+}
+
 func BenchmarkStaticToken(b *testing.B) {
 	r, _ := http.NewRequest("GET", "/", nil)
 	r.Header.Set("Authorization", "Bearer token")
@@ -24,6 +24,14 @@ var (
 	// when token not found in their store.
 	ErrTokenNotFound = errors.New("strategies/token: Token does not exists")
 
+	// ErrTokenExpired is returned by static token strategy,
+	// when token found in its store but it has expired.
+	ErrTokenExpired = errors.New("strategies/token: Token has expired")
+
+	// ErrTokenNotYetValid is returned by static token strategy,
+	// when token found in its store but it's not valid yet.
+	ErrTokenNotYetValid = errors.New("strategies/token: Token not yet valid")
+
 	// ErrNOOP is a soft error similar to EOF,
 	// returned by NoOpAuthenticate function to indicate there no op,
 	// and signal the caller to unauthenticate the request.
@@ -56,6 +64,9 @@ type core struct {
 	strategy strategy
 	hasher   internal.Hasher
 	verify   verify
+	dpop     *dpop
+	bound    bool
+	clock    auth.Clock
 }
 
 func (c *core) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
@@ -64,8 +75,10 @@ func (c *core) Authenticate(ctx context.Context, r *http.Request) (auth.Info, er
 		return nil, err
 	}
 
+	// the strategy stores and looks up the token by its hash,
+	// while the token itself passed on to the authenticate func.
 	hash := c.hasher.Hash(token)
-	info, err := c.strategy.authenticate(ctx, r, token, hash)
+	info, err := c.strategy.authenticate(ctx, r, hash, token)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +87,18 @@ func (c *core) Authenticate(ctx context.Context, r *http.Request) (auth.Info, er
 		return nil, err
 	}
 
+	if c.bound {
+		if err := verifyCertificate(ctx, r, info, token); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.dpop.enabled {
+		if err := c.dpop.verify(ctx, r, info, token); err != nil {
+			return nil, err
+		}
+	}
+
 	return info, nil
 }
 
@@ -93,11 +118,20 @@ func (c *core) Revoke(token interface{}) error {
 	return auth.NewTypeError("strategies/token:", "str", token)
 }
 
+// hasherFunc is an adapter to allow the use of ordinary functions as internal.Hasher.
+type hasherFunc func(string) string
+
+func (fn hasherFunc) Hash(str string) string {
+	return fn(str)
+}
+
 func newCore(s strategy, opts ...auth.Option) *core {
 	c := new(core)
 	c.strategy = s
 	c.hasher = internal.PlainTextHasher{}
 	c.parser = AuthorizationParser(string(Bearer))
+	c.dpop = newDPoP()
+	c.clock = auth.SystemClock
 	c.verify = func(_ context.Context, _ *http.Request, _ auth.Info, _ string) error {
 		return nil
 	}
@@ -106,5 +140,7 @@ func newCore(s strategy, opts ...auth.Option) *core {
 		opt.Apply(c)
 	}
 
+	c.dpop.clock = c.clock
+
 	return c
 }
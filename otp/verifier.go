@@ -3,6 +3,8 @@ package otp
 import (
 	"errors"
 	"time"
+
+	"github.com/shaj13/go-guardian/v2/auth"
 )
 
 // ErrMaxAttempts is returned by Verifier,
@@ -47,6 +49,16 @@ type Verifier struct {
 	DealyTime time.Time
 	// Key represnt Uri Format for OTP.
 	Key *Key
+	// Clock provides the current time to compute TOTP interval and lockout delay.
+	// Default auth.SystemClock.
+	Clock auth.Clock
+}
+
+func (v *Verifier) now() time.Time {
+	if v.Clock == nil {
+		return auth.SystemClock.Now().UTC()
+	}
+	return v.Clock.Now().UTC()
 }
 
 func (v *Verifier) lockOut() error {
@@ -59,7 +71,7 @@ func (v *Verifier) lockOut() error {
 		return ErrMaxAttempts
 	}
 
-	if remaining := v.DealyTime.UTC().Sub(time.Now().UTC()); remaining > 0 {
+	if remaining := v.DealyTime.UTC().Sub(v.now()); remaining > 0 {
 		return VerificationDisabledError(remaining)
 	}
 
@@ -88,7 +100,7 @@ func (v *Verifier) updateLockOut(valid bool) {
 	}
 
 	v.Failures++
-	v.DealyTime = time.Now().UTC().Add(time.Second * time.Duration(v.Failures*v.LockOutDelay))
+	v.DealyTime = v.now().Add(time.Second * time.Duration(v.Failures*v.LockOutDelay))
 }
 
 func (v *Verifier) interval() uint64 {
@@ -99,7 +111,7 @@ func (v *Verifier) interval() uint64 {
 		return counter
 	}
 
-	return uint64(time.Now().UTC().Unix()) / v.Key.Period()
+	return uint64(v.now().Unix()) / v.Key.Period()
 }
 
 // Verify one-time password.
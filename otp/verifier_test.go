@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/v2/auth/authtest"
 )
 
 func TestNew(t *testing.T) {
@@ -83,6 +85,23 @@ func TestVerifierVerify(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestVerifierClock(t *testing.T) {
+	// RFC 6238 appendix B test vector at 59 seconds.
+	key := NewKey(TOTP, "label", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	ver := New(key)
+	ver.Skew = 0
+	ver.Clock = authtest.NewFakeClock(time.Unix(59, 0))
+
+	ok, err := ver.Verify("287082")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ver.Clock = authtest.NewFakeClock(time.Unix(2000000000, 0))
+	ok, err = ver.Verify("279037")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
 func TestLockOutE2E(t *testing.T) {
 	// Round #1 check if verification disabled when lockout start from 0
 	v := &Verifier{